@@ -0,0 +1,238 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package cfntest runs CloudFormation custom resources locally, for use in tests.
+//
+// A Harness serves the ResponseURL of the events it sends from a local HTTP server,
+// so a function wrapped with cfn.LambdaWrap can be tested as it is, without mocking the HTTP client.
+//
+//	func TestBucket(t *testing.T) {
+//		h := cfntest.New(cfn.LambdaWrap(bucket))
+//		defer h.Close()
+//
+//		responses := h.AssertLifecycle(t, map[string]interface{}{"Name": "a"}, map[string]interface{}{"Name": "b"})
+//		assert.Equal(t, "a", responses[0].Data["Name"])
+//	}
+package cfntest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+)
+
+// Harness sends custom resource events to a function and captures the responses sent to CloudFormation.
+// It keeps track of the physical resource ID and properties of the resource between requests,
+// like CloudFormation does for a resource in a stack.
+type Harness struct {
+	ResourceType      string
+	LogicalResourceID string
+	StackID           string
+
+	fn     cfn.CustomResourceLambdaFunction
+	server *httptest.Server
+
+	mu        sync.Mutex
+	responses map[string][]cfn.Response
+	requests  int
+
+	physicalResourceID string
+	properties         map[string]interface{}
+}
+
+// New returns a Harness for the function, which is usually the result of cfn.LambdaWrap.
+// The Harness must be closed with Close when it is no longer needed.
+func New(fn cfn.CustomResourceLambdaFunction) *Harness {
+	h := &Harness{
+		ResourceType:      "Custom::Test",
+		LogicalResourceID: "TestResource",
+		StackID:           "arn:aws:cloudformation:us-east-1:123456789012:stack/cfntest/00000000-0000-0000-0000-000000000000",
+		fn:                fn,
+		responses:         make(map[string][]cfn.Response),
+	}
+	h.server = httptest.NewServer(http.HandlerFunc(h.serveResponse))
+	return h
+}
+
+// Close shuts down the local ResponseURL server.
+func (h *Harness) Close() {
+	h.server.Close()
+}
+
+func (h *Harness) serveResponse(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var response cfn.Response
+	if err := json.NewDecoder(r.Body).Decode(&response); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	requestID := strings.TrimPrefix(r.URL.Path, "/")
+	h.mu.Lock()
+	h.responses[requestID] = append(h.responses[requestID], response)
+	h.mu.Unlock()
+}
+
+// Event returns the event CloudFormation would send for the request type, given the current state of the resource.
+// For Update, the current properties become the OldResourceProperties. For Delete, properties is ignored
+// and the current properties are sent.
+func (h *Harness) Event(requestType cfn.RequestType, properties map[string]interface{}) cfn.Event {
+	h.mu.Lock()
+	physicalResourceID, currentProperties := h.physicalResourceID, h.properties
+	h.mu.Unlock()
+
+	event := h.newEvent(requestType, physicalResourceID, properties)
+	switch requestType {
+	case cfn.RequestCreate:
+		event.PhysicalResourceID = ""
+	case cfn.RequestUpdate:
+		event.OldResourceProperties = currentProperties
+	case cfn.RequestDelete:
+		event.ResourceProperties = currentProperties
+	}
+	return event
+}
+
+func (h *Harness) newEvent(requestType cfn.RequestType, physicalResourceID string, properties map[string]interface{}) cfn.Event {
+	h.mu.Lock()
+	h.requests++
+	requestID := fmt.Sprintf("cfntest-%d", h.requests)
+	h.mu.Unlock()
+
+	return cfn.Event{
+		RequestType:        requestType,
+		RequestID:          requestID,
+		ResponseURL:        h.server.URL + "/" + requestID,
+		ResourceType:       h.ResourceType,
+		PhysicalResourceID: physicalResourceID,
+		LogicalResourceID:  h.LogicalResourceID,
+		StackID:            h.StackID,
+		ResourceProperties: properties,
+	}
+}
+
+// Send sends the event to the function and returns the response it sent to the ResponseURL.
+// It is an error for the function to send no response, or more than one.
+func (h *Harness) Send(ctx context.Context, event cfn.Event) (cfn.Response, error) {
+	reason, err := h.fn(ctx, event)
+	if err != nil {
+		return cfn.Response{}, err
+	}
+	if reason != "" {
+		return cfn.Response{}, fmt.Errorf("cfntest: failed to send response: %s", reason)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	responses := h.responses[event.RequestID]
+	switch len(responses) {
+	case 0:
+		return cfn.Response{}, errors.New("cfntest: no response was sent")
+	case 1:
+	default:
+		return cfn.Response{}, fmt.Errorf("cfntest: %d responses were sent, CloudFormation only accepts the first", len(responses))
+	}
+
+	response := responses[0]
+	if response.Status == cfn.StatusSuccess && event.RequestType != cfn.RequestDelete {
+		h.physicalResourceID = response.PhysicalResourceID
+		h.properties = event.ResourceProperties
+	}
+	return response, nil
+}
+
+// Create sends a Create request for a new resource with the properties.
+func (h *Harness) Create(ctx context.Context, properties map[string]interface{}) (cfn.Response, error) {
+	return h.Send(ctx, h.Event(cfn.RequestCreate, properties))
+}
+
+// Update sends an Update request that changes the properties of the resource.
+// If the function replaces the resource by returning a new physical resource ID, the old resource is
+// deleted afterwards with a Delete request for the old ID and properties, as CloudFormation does
+// in the cleanup phase of the stack update. It is an error for that Delete to fail.
+func (h *Harness) Update(ctx context.Context, properties map[string]interface{}) (cfn.Response, error) {
+	response, _, err := h.update(ctx, properties)
+	return response, err
+}
+
+// update sends an Update request, and the cleanup Delete request if the resource was replaced.
+func (h *Harness) update(ctx context.Context, properties map[string]interface{}) (response cfn.Response, cleanup *cfn.Response, err error) {
+	event := h.Event(cfn.RequestUpdate, properties)
+	response, err = h.Send(ctx, event)
+	if err != nil || response.Status != cfn.StatusSuccess || response.PhysicalResourceID == event.PhysicalResourceID {
+		return response, nil, err
+	}
+
+	deleted, err := h.Send(ctx, h.newEvent(cfn.RequestDelete, event.PhysicalResourceID, event.OldResourceProperties))
+	if err != nil {
+		return response, nil, fmt.Errorf("cfntest: cleanup Delete of replaced resource %s: %w", event.PhysicalResourceID, err)
+	}
+	if deleted.Status != cfn.StatusSuccess {
+		return response, &deleted, fmt.Errorf("cfntest: cleanup Delete of replaced resource %s returned %s: %s",
+			event.PhysicalResourceID, deleted.Status, deleted.Reason)
+	}
+	return response, &deleted, nil
+}
+
+// Delete sends a Delete request for the resource.
+func (h *Harness) Delete(ctx context.Context) (cfn.Response, error) {
+	return h.Send(ctx, h.Event(cfn.RequestDelete, nil))
+}
+
+// Lifecycle creates the resource, updates it to the updated properties and deletes it,
+// returning the responses in the order they were sent: Create, Update, the cleanup Delete
+// of the old resource if the Update replaced it, and Delete.
+// It stops at the first request that fails to respond.
+func (h *Harness) Lifecycle(ctx context.Context, properties, updatedProperties map[string]interface{}) ([]cfn.Response, error) {
+	var responses []cfn.Response
+	response, err := h.Create(ctx, properties)
+	if err != nil {
+		return responses, err
+	}
+	responses = append(responses, response)
+
+	response, cleanup, err := h.update(ctx, updatedProperties)
+	if err != nil {
+		return responses, err
+	}
+	responses = append(responses, response)
+	if cleanup != nil {
+		responses = append(responses, *cleanup)
+	}
+
+	response, err = h.Delete(ctx)
+	if err != nil {
+		return responses, err
+	}
+	return append(responses, response), nil
+}
+
+// AssertLifecycle runs Lifecycle and fails the test unless every request succeeds
+// and the physical resource ID stays the same from Create to Delete.
+// A resource that is replaced on Update should be tested with Lifecycle instead.
+func (h *Harness) AssertLifecycle(t testing.TB, properties, updatedProperties map[string]interface{}) []cfn.Response {
+	t.Helper()
+	responses, err := h.Lifecycle(context.Background(), properties, updatedProperties)
+	if err != nil {
+		t.Fatalf("lifecycle of %s failed: %v", h.LogicalResourceID, err)
+	}
+	for i, response := range responses {
+		if response.Status != cfn.StatusSuccess {
+			t.Errorf("request %d of the lifecycle of %s returned %s: %s", i+1, h.LogicalResourceID, response.Status, response.Reason)
+		}
+		if response.PhysicalResourceID != responses[0].PhysicalResourceID {
+			t.Errorf("request %d of the lifecycle of %s changed the PhysicalResourceId from %q to %q",
+				i+1, h.LogicalResourceID, responses[0].PhysicalResourceID, response.PhysicalResourceID)
+		}
+	}
+	return responses
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfntest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/cfn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssertLifecycle(t *testing.T) {
+	var events []cfn.Event
+	h := New(cfn.LambdaWrap(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		events = append(events, event)
+		if event.RequestType == cfn.RequestCreate {
+			return "bucket-" + event.ResourceProperties["Name"].(string), nil, nil
+		}
+		return event.PhysicalResourceID, map[string]interface{}{"Name": event.ResourceProperties["Name"]}, nil
+	}))
+	defer h.Close()
+
+	responses := h.AssertLifecycle(t, map[string]interface{}{"Name": "a"}, map[string]interface{}{"Name": "b"})
+	require.Len(t, responses, 3)
+	assert.Equal(t, "b", responses[1].Data["Name"])
+
+	require.Len(t, events, 3)
+	assert.Equal(t, "", events[0].PhysicalResourceID)
+	assert.Equal(t, "bucket-a", events[1].PhysicalResourceID)
+	assert.Equal(t, map[string]interface{}{"Name": "a"}, events[1].OldResourceProperties)
+	assert.Equal(t, map[string]interface{}{"Name": "b"}, events[2].ResourceProperties)
+	for _, event := range events {
+		assert.Equal(t, h.LogicalResourceID, event.LogicalResourceID)
+		assert.Contains(t, event.ResponseURL, event.RequestID)
+	}
+}
+
+func TestLifecycleReplacement(t *testing.T) {
+	var deleted []string
+	var deletedProperties []interface{}
+	h := New(cfn.LambdaWrap(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		if event.RequestType == cfn.RequestDelete {
+			deleted = append(deleted, event.PhysicalResourceID)
+			deletedProperties = append(deletedProperties, event.ResourceProperties["Name"])
+			return event.PhysicalResourceID, nil, nil
+		}
+		return "bucket-" + event.ResourceProperties["Name"].(string), nil, nil
+	}))
+	defer h.Close()
+
+	responses, err := h.Lifecycle(context.Background(), map[string]interface{}{"Name": "a"}, map[string]interface{}{"Name": "b"})
+	require.NoError(t, err)
+	require.Len(t, responses, 4)
+	assert.Equal(t, "bucket-a", responses[0].PhysicalResourceID)
+	assert.Equal(t, "bucket-b", responses[1].PhysicalResourceID)
+	assert.Equal(t, "bucket-a", responses[2].PhysicalResourceID)
+	assert.Equal(t, "bucket-b", responses[3].PhysicalResourceID)
+	assert.Equal(t, []string{"bucket-a", "bucket-b"}, deleted)
+	assert.Equal(t, []interface{}{"a", "b"}, deletedProperties)
+}
+
+func TestFailedCleanupDelete(t *testing.T) {
+	h := New(cfn.LambdaWrap(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		if event.RequestType == cfn.RequestDelete {
+			return "", nil, errors.New("bucket not empty")
+		}
+		return "bucket-" + event.ResourceProperties["Name"].(string), nil, nil
+	}))
+	defer h.Close()
+
+	_, err := h.Create(context.Background(), map[string]interface{}{"Name": "a"})
+	require.NoError(t, err)
+	response, err := h.Update(context.Background(), map[string]interface{}{"Name": "b"})
+	assert.EqualError(t, err, "cfntest: cleanup Delete of replaced resource bucket-a returned FAILED: bucket not empty")
+	assert.Equal(t, "bucket-b", response.PhysicalResourceID)
+}
+
+func TestSendFailures(t *testing.T) {
+	h := New(cfn.LambdaWrap(func(ctx context.Context, event cfn.Event) (string, map[string]interface{}, error) {
+		return "", nil, errors.New("access denied")
+	}))
+	defer h.Close()
+
+	response, err := h.Create(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, cfn.StatusFailed, response.Status)
+	assert.Equal(t, "access denied", response.Reason)
+
+	silent := New(func(ctx context.Context, event cfn.Event) (string, error) {
+		return "", nil
+	})
+	defer silent.Close()
+	_, err = silent.Create(context.Background(), nil)
+	assert.EqualError(t, err, "cfntest: no response was sent")
+}
@@ -0,0 +1,143 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// HookInvocationPoint represents the point in a provisioning operation at which a hook is invoked.
+type HookInvocationPoint string
+
+const (
+	HookCreatePreProvision HookInvocationPoint = "CREATE_PRE_PROVISION"
+	HookUpdatePreProvision HookInvocationPoint = "UPDATE_PRE_PROVISION"
+	HookDeletePreProvision HookInvocationPoint = "DELETE_PRE_PROVISION"
+)
+
+// HookStatus represents the status of a CloudFormation hook response.
+type HookStatus string
+
+const (
+	HookStatusSuccess    HookStatus = "SUCCESS"
+	HookStatusFailed     HookStatus = "FAILED"
+	HookStatusInProgress HookStatus = "IN_PROGRESS"
+)
+
+// HookErrorCode represents the reason a CloudFormation hook failed.
+type HookErrorCode string
+
+const (
+	HookErrorCodeNonCompliant    HookErrorCode = "NonCompliant"
+	HookErrorCodeInternalFailure HookErrorCode = "InternalFailure"
+)
+
+// HookEvent is the input of a Lambda function invoked by a CloudFormation Lambda hook.
+//
+// See https://docs.aws.amazon.com/cloudformation-cli/latest/hooks-userguide/lambda-hooks.html
+type HookEvent struct {
+	ClientRequestToken    string                 `json:"clientRequestToken"`
+	AWSAccountID          string                 `json:"awsAccountId"`
+	StackID               string                 `json:"stackId"`
+	ChangeSetID           string                 `json:"changeSetId,omitempty"`
+	HookTypeName          string                 `json:"hookTypeName"`
+	HookTypeVersion       string                 `json:"hookTypeVersion"`
+	HookModel             map[string]interface{} `json:"hookModel,omitempty"`
+	ActionInvocationPoint HookInvocationPoint    `json:"actionInvocationPoint"`
+	RequestData           HookRequestData        `json:"requestData"`
+	RequestContext        HookRequestContext     `json:"requestContext"`
+}
+
+// HookRequestData describes the target a hook is invoked for.
+// TargetType is a resource type such as AWS::S3::Bucket, or STACK or CHANGE_SET for hooks that target a whole stack.
+type HookRequestData struct {
+	TargetName      string           `json:"targetName"`
+	TargetType      string           `json:"targetType"`
+	TargetLogicalID string           `json:"targetLogicalId"`
+	TargetModel     *HookTargetModel `json:"targetModel,omitempty"`
+	// Payload is a pre-signed URL to the template or change set, for hooks that target a whole stack.
+	Payload string `json:"payload,omitempty"`
+}
+
+// HookTargetModel holds the properties of the resource a hook is invoked for.
+type HookTargetModel struct {
+	ResourceProperties         map[string]interface{} `json:"resourceProperties"`
+	PreviousResourceProperties map[string]interface{} `json:"previousResourceProperties,omitempty"`
+}
+
+// HookRequestContext holds the state of a hook that previously responded IN_PROGRESS.
+type HookRequestContext struct {
+	Invocation      int                    `json:"invocation"`
+	CallbackContext map[string]interface{} `json:"callbackContext,omitempty"`
+}
+
+// HookResponse is the output of a Lambda function invoked by a CloudFormation Lambda hook.
+type HookResponse struct {
+	HookStatus           HookStatus             `json:"hookStatus"`
+	ErrorCode            HookErrorCode          `json:"errorCode,omitempty"`
+	Message              string                 `json:"message,omitempty"`
+	ClientRequestToken   string                 `json:"clientRequestToken"`
+	CallbackContext      map[string]interface{} `json:"callbackContext,omitempty"`
+	CallbackDelaySeconds int                    `json:"callbackDelaySeconds,omitempty"`
+}
+
+// HookError is returned by a HookFunction to fail a hook with a specific error code.
+type HookError struct {
+	Code    HookErrorCode
+	Message string
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// NonCompliant returns a HookError that fails the hook because the target does not comply with it.
+func NonCompliant(format string, args ...interface{}) error {
+	return &HookError{Code: HookErrorCodeNonCompliant, Message: fmt.Sprintf(format, args...)}
+}
+
+// HookFunction is a representation of the customer's hook function.
+// Returning nil lets the provisioning operation continue. Returning an error created
+// by NonCompliant fails the hook with that message, and any other error fails it as an InternalFailure.
+type HookFunction func(context.Context, HookEvent) error
+
+// HookLambdaFunction is a standard form Lambda for a CloudFormation Lambda hook.
+type HookLambdaFunction func(context.Context, HookEvent) (HookResponse, error)
+
+// LambdaWrapHook returns a HookLambdaFunction which is something lambda.Start()
+// will understand. Functions that need to respond IN_PROGRESS should return a HookResponse directly instead.
+//
+//	func myHook(ctx context.Context, event cfn.HookEvent) error {
+//		if event.RequestData.TargetModel.ResourceProperties["BucketEncryption"] == nil {
+//			return cfn.NonCompliant("bucket %s must be encrypted", event.RequestData.TargetLogicalID)
+//		}
+//		return nil
+//	}
+//
+//	func main() {
+//		lambda.Start(cfn.LambdaWrapHook(myHook))
+//	}
+func LambdaWrapHook(hookFunction HookFunction) HookLambdaFunction {
+	return func(ctx context.Context, event HookEvent) (HookResponse, error) {
+		response := HookResponse{ClientRequestToken: event.ClientRequestToken}
+
+		err := hookFunction(ctx, event)
+		if err == nil {
+			response.HookStatus = HookStatusSuccess
+			return response, nil
+		}
+
+		response.HookStatus = HookStatusFailed
+		var hookErr *HookError
+		if errors.As(err, &hookErr) {
+			response.ErrorCode = hookErr.Code
+			response.Message = hookErr.Message
+		} else {
+			response.ErrorCode = HookErrorCodeInternalFailure
+			response.Message = err.Error()
+		}
+		return response, nil
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil" //nolint: staticcheck
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookEventMarshaling(t *testing.T) {
+	inputJSON, err := ioutil.ReadFile("./testdata/hook-event.json")
+	require.NoError(t, err)
+
+	var event HookEvent
+	require.NoError(t, json.Unmarshal(inputJSON, &event))
+	assert.Equal(t, HookCreatePreProvision, event.ActionInvocationPoint)
+	assert.Equal(t, "my-bucket", event.RequestData.TargetModel.ResourceProperties["BucketName"])
+
+	outputJSON, err := json.Marshal(event)
+	require.NoError(t, err)
+	test.AssertJsonsEqual(t, inputJSON, outputJSON)
+}
+
+func TestLambdaWrapHook(t *testing.T) {
+	tests := map[string]struct {
+		err      error
+		expected HookResponse
+	}{
+		"success": {
+			expected: HookResponse{HookStatus: HookStatusSuccess, ClientRequestToken: "token"},
+		},
+		"non compliant": {
+			err:      fmt.Errorf("checking bucket: %w", NonCompliant("bucket %s must be encrypted", "MyBucket")),
+			expected: HookResponse{HookStatus: HookStatusFailed, ErrorCode: HookErrorCodeNonCompliant, Message: "bucket MyBucket must be encrypted", ClientRequestToken: "token"},
+		},
+		"internal failure": {
+			err:      errors.New("could not read template"),
+			expected: HookResponse{HookStatus: HookStatusFailed, ErrorCode: HookErrorCodeInternalFailure, Message: "could not read template", ClientRequestToken: "token"},
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			fn := LambdaWrapHook(func(ctx context.Context, event HookEvent) error {
+				return test.err
+			})
+			response, err := fn(context.TODO(), HookEvent{ClientRequestToken: "token"})
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, response)
+		})
+	}
+}
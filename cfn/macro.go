@@ -0,0 +1,76 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+)
+
+// MacroStatus represents the status of a CloudFormation macro response.
+// Any status other than MacroStatusSuccess is treated as a failure by CloudFormation.
+type MacroStatus string
+
+const (
+	MacroStatusSuccess MacroStatus = "success"
+	MacroStatusFailure MacroStatus = "failure"
+)
+
+// MacroRequest is the input of a Lambda function that processes a CloudFormation macro.
+//
+// See https://docs.aws.amazon.com/AWSCloudFormation/latest/UserGuide/template-macros.html
+type MacroRequest struct {
+	Region                  string                 `json:"region"`
+	AccountID               string                 `json:"accountId"`
+	Fragment                map[string]interface{} `json:"fragment"`
+	TransformID             string                 `json:"transformId"`
+	Params                  map[string]interface{} `json:"params"`
+	RequestID               string                 `json:"requestId"`
+	TemplateParameterValues map[string]interface{} `json:"templateParameterValues"`
+}
+
+// MacroResponse is the output of a Lambda function that processes a CloudFormation macro.
+type MacroResponse struct {
+	RequestID    string                 `json:"requestId"`
+	Status       MacroStatus            `json:"status"`
+	Fragment     map[string]interface{} `json:"fragment"`
+	ErrorMessage string                 `json:"errorMessage,omitempty"`
+}
+
+// MacroFunction is a representation of the customer's macro function.
+// It returns the processed template fragment.
+type MacroFunction func(context.Context, MacroRequest) (fragment map[string]interface{}, err error)
+
+// MacroLambdaFunction is a standard form Lambda for a CloudFormation macro.
+type MacroLambdaFunction func(context.Context, MacroRequest) (MacroResponse, error)
+
+// LambdaWrapMacro returns a MacroLambdaFunction which is something lambda.Start()
+// will understand. The returned fragment is sent back with the request ID,
+// and an error is reported to CloudFormation as a failed transform with the error as the message.
+//
+//	func myMacro(ctx context.Context, request cfn.MacroRequest) (fragment map[string]interface{}, err error) {
+//		fragment = request.Fragment
+//		...
+//		return
+//	}
+//
+//	func main() {
+//		lambda.Start(cfn.LambdaWrapMacro(myMacro))
+//	}
+func LambdaWrapMacro(macroFunction MacroFunction) MacroLambdaFunction {
+	return func(ctx context.Context, request MacroRequest) (MacroResponse, error) {
+		response := MacroResponse{RequestID: request.RequestID}
+
+		fragment, err := macroFunction(ctx, request)
+		if err != nil {
+			response.Status = MacroStatusFailure
+			response.ErrorMessage = err.Error()
+			// CloudFormation requires a fragment even on failure.
+			response.Fragment = request.Fragment
+			return response, nil
+		}
+
+		response.Status = MacroStatusSuccess
+		response.Fragment = fragment
+		return response, nil
+	}
+}
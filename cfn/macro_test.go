@@ -0,0 +1,61 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil" //nolint: staticcheck
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMacroRequestMarshaling(t *testing.T) {
+	inputJSON, err := ioutil.ReadFile("./testdata/macro-request.json")
+	require.NoError(t, err)
+
+	var request MacroRequest
+	require.NoError(t, json.Unmarshal(inputJSON, &request))
+	assert.Equal(t, "123456789012::AddTags", request.TransformID)
+	assert.Equal(t, "prod", request.TemplateParameterValues["Environment"])
+
+	outputJSON, err := json.Marshal(request)
+	require.NoError(t, err)
+	test.AssertJsonsEqual(t, inputJSON, outputJSON)
+}
+
+func TestLambdaWrapMacro(t *testing.T) {
+	request := MacroRequest{
+		RequestID: "request-id",
+		Fragment:  map[string]interface{}{"Resources": map[string]interface{}{}},
+		Params:    map[string]interface{}{"Fail": false},
+	}
+	fn := LambdaWrapMacro(func(ctx context.Context, request MacroRequest) (map[string]interface{}, error) {
+		if request.Params["Fail"] == true {
+			return nil, errors.New("invalid Tags parameter")
+		}
+		return map[string]interface{}{"Resources": map[string]interface{}{"Added": true}}, nil
+	})
+
+	response, err := fn(context.TODO(), request)
+	require.NoError(t, err)
+	assert.Equal(t, MacroResponse{
+		RequestID: "request-id",
+		Status:    MacroStatusSuccess,
+		Fragment:  map[string]interface{}{"Resources": map[string]interface{}{"Added": true}},
+	}, response)
+
+	request.Params["Fail"] = true
+	response, err = fn(context.TODO(), request)
+	require.NoError(t, err)
+	assert.Equal(t, MacroResponse{
+		RequestID:    "request-id",
+		Status:       MacroStatusFailure,
+		Fragment:     request.Fragment,
+		ErrorMessage: "invalid Tags parameter",
+	}, response)
+}
@@ -0,0 +1,90 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// DefaultTimeoutMargin is how long before the Lambda deadline a wrapped function is considered to have timed out.
+// It leaves time for the FAILED response to be sent. Retries that would not complete before the deadline are skipped.
+const DefaultTimeoutMargin = time.Second
+
+// Option configures the behavior of LambdaWrap and Response.Send.
+type Option func(*options)
+
+type options struct {
+	client        HTTPClient
+	retry         RetryPolicy
+	timeoutMargin time.Duration
+	continuation  func(ctx context.Context, event Event) error
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		client:        http.DefaultClient,
+		retry:         DefaultRetryPolicy,
+		timeoutMargin: DefaultTimeoutMargin,
+	}
+	for _, option := range opts {
+		option(o)
+	}
+	return o
+}
+
+// HTTPClient sends the HTTP request of a Response. *http.Client satisfies this interface.
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WithHTTPClient sets the client used to send responses to CloudFormation. The default is http.DefaultClient.
+func WithHTTPClient(client HTTPClient) Option {
+	return Option(func(o *options) {
+		o.client = client
+	})
+}
+
+// RetryPolicy configures how sending a response is retried after a network error or a 5xx status code.
+// The delay before each retry doubles, starting from InitialBackoff, up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts    int // MaxAttempts includes the first attempt. Values less than 2 disable retries.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy is the RetryPolicy used unless WithRetry is given.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+// WithRetry sets the retry policy used to send responses to CloudFormation. The default is DefaultRetryPolicy.
+func WithRetry(policy RetryPolicy) Option {
+	return Option(func(o *options) {
+		o.retry = policy
+	})
+}
+
+// WithTimeoutMargin sets how long before the Lambda deadline the wrapped function is considered to have timed out.
+// At that point, a FAILED response is sent to CloudFormation, so that the stack does not wait for the
+// custom resource timeout. The context passed to the function expires at the same time.
+// Retries of the response are only made while their backoff ends before the Lambda deadline.
+// The default is DefaultTimeoutMargin.
+func WithTimeoutMargin(margin time.Duration) Option {
+	return Option(func(o *options) {
+		o.timeoutMargin = margin
+	})
+}
+
+// WithContinuation sets a function that is called instead of sending a FAILED response when the wrapped function times out.
+// This allows long running work to continue in another invoke, for example by invoking the current function again with the same event.
+// The continuation is then responsible for the response, and no response is sent for the current invoke.
+// If the continuation returns an error, a FAILED response is sent instead.
+func WithContinuation(continuation func(ctx context.Context, event Event) error) Option {
+	return Option(func(o *options) {
+		o.continuation = continuation
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DecodeProperties decodes resource properties, such as Event.ResourceProperties, into out, which must be a pointer.
+// Properties are matched to struct fields the same way as encoding/json.
+//
+// CloudFormation sends every scalar property value as a string, so strings are converted to numbers
+// and booleans when the destination field requires it. A property of "42" may be decoded into an int field,
+// and a property of "true" into a bool field.
+func DecodeProperties(properties map[string]interface{}, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("cfn: DecodeProperties requires a non-nil pointer, got %T", out)
+	}
+	coerced, err := coerceProperty(properties, v.Type().Elem(), "")
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(coerced)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return fmt.Errorf("cfn: failed to decode properties: %v", err)
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// coerceProperty converts string values into the JSON type required to decode value into a t.
+func coerceProperty(value interface{}, t reflect.Type, path string) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) {
+		return value, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		if s, ok := value.(string); ok {
+			b, err := strconv.ParseBool(s)
+			if err != nil {
+				return nil, fmt.Errorf("cfn: property %s: cannot convert %q to bool", path, s)
+			}
+			return b, nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if s, ok := value.(string); ok {
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("cfn: property %s: cannot convert %q to %s", path, s, t.Kind())
+			}
+			return json.Number(s), nil
+		}
+	case reflect.Slice, reflect.Array:
+		if values, ok := value.([]interface{}); ok {
+			out := make([]interface{}, len(values))
+			for i, v := range values {
+				var err error
+				if out[i], err = coerceProperty(v, t.Elem(), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+	case reflect.Map:
+		if values, ok := value.(map[string]interface{}); ok {
+			out := make(map[string]interface{}, len(values))
+			for k, v := range values {
+				var err error
+				if out[k], err = coerceProperty(v, t.Elem(), joinPropertyPath(path, k)); err != nil {
+					return nil, err
+				}
+			}
+			return out, nil
+		}
+	case reflect.Struct:
+		if values, ok := value.(map[string]interface{}); ok {
+			out := make(map[string]interface{}, len(values))
+			for k, v := range values {
+				out[k] = v
+				if field, ok := findField(t, k); ok {
+					var err error
+					if out[k], err = coerceProperty(v, field.Type, joinPropertyPath(path, k)); err != nil {
+						return nil, err
+					}
+				}
+			}
+			return out, nil
+		}
+	}
+	return value, nil
+}
+
+// findField finds the struct field that encoding/json would decode the key into.
+func findField(t reflect.Type, key string) (reflect.StructField, bool) {
+	var fold reflect.StructField
+	var folded bool
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				if f, ok := findField(embedded, key); ok {
+					return f, true
+				}
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if name == key {
+			return field, true
+		}
+		if !folded && strings.EqualFold(name, key) {
+			fold, folded = field, true
+		}
+	}
+	return fold, folded
+}
+
+func joinPropertyPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
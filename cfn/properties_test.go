@@ -0,0 +1,80 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testTags struct {
+	Key   string
+	Value string
+}
+
+type testEmbedded struct {
+	Retries int
+}
+
+type testProperties struct {
+	testEmbedded
+	Name      string
+	Versioned bool
+	Count     int               `json:"count"`
+	Ratio     *float64          `json:"Ratio,omitempty"`
+	Ports     []uint16          `json:"Ports"`
+	Limits    map[string]int64  `json:"Limits"`
+	Tags      []testTags        `json:"Tags"`
+	Raw       json.RawMessage   `json:"Raw"`
+	Labels    map[string]string `json:"Labels"`
+	Ignored   int               `json:"-"`
+}
+
+func TestDecodeProperties(t *testing.T) {
+	var properties map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"ServiceToken": "arn:aws:lambda:us-east-1:123456789012:function:custom-resource",
+		"Name": "bucket",
+		"versioned": "true",
+		"count": "3",
+		"Ratio": "0.5",
+		"Ports": ["80", "443"],
+		"Limits": {"a": "1", "b": "2"},
+		"Tags": [{"Key": "k", "Value": "1"}],
+		"Raw": {"Nested": "1"},
+		"Labels": {"a": "1"},
+		"Retries": "5",
+		"Ignored": "not a number"
+	}`), &properties))
+
+	var decoded testProperties
+	require.NoError(t, DecodeProperties(properties, &decoded))
+	ratio := 0.5
+	assert.Equal(t, testProperties{
+		testEmbedded: testEmbedded{Retries: 5},
+		Name:         "bucket",
+		Versioned:    true,
+		Count:        3,
+		Ratio:        &ratio,
+		Ports:        []uint16{80, 443},
+		Limits:       map[string]int64{"a": 1, "b": 2},
+		Tags:         []testTags{{Key: "k", Value: "1"}},
+		Raw:          json.RawMessage(`{"Nested":"1"}`),
+		Labels:       map[string]string{"a": "1"},
+	}, decoded)
+}
+
+func TestDecodePropertiesErrors(t *testing.T) {
+	var decoded testProperties
+	assert.EqualError(t, DecodeProperties(map[string]interface{}{"Versioned": "yes please"}, &decoded),
+		`cfn: property Versioned: cannot convert "yes please" to bool`)
+	assert.EqualError(t, DecodeProperties(map[string]interface{}{"Ports": []interface{}{"80", "http"}}, &decoded),
+		`cfn: property Ports[1]: cannot convert "http" to uint16`)
+	assert.EqualError(t, DecodeProperties(map[string]interface{}{"Limits": map[string]interface{}{"a": "x"}}, &decoded),
+		`cfn: property Limits.a: cannot convert "x" to int64`)
+	assert.Error(t, DecodeProperties(map[string]interface{}{"Ports": []interface{}{"-1"}}, &decoded))
+	assert.Error(t, DecodeProperties(nil, decoded))
+}
@@ -4,11 +4,15 @@ package cfn
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil" //nolint: staticcheck
 	"log"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 // StatusType represents a CloudFormation response status
@@ -46,44 +50,116 @@ func NewResponse(r *Event) *Response {
 	}
 }
 
-type httpClient interface {
-	Do(req *http.Request) (*http.Response, error)
-}
+// maxResponseSize is the largest response body accepted by CloudFormation.
+const maxResponseSize = 4096
 
-func (r *Response) sendWith(client httpClient) error {
+// marshal encodes the response, truncating the Reason if the response would exceed maxResponseSize.
+// If the response is still too large, the Data is dropped and the response is changed to FAILED.
+// It works on a copy, so r is left as it is.
+func (r Response) marshal() ([]byte, error) {
 	body, err := json.Marshal(r)
+	for err == nil && len(body) > maxResponseSize && r.Reason != "" {
+		const ellipsis = "..."
+		keep := len(r.Reason) - (len(body) - maxResponseSize) - len(ellipsis)
+		r.Reason = truncateUTF8(strings.TrimSuffix(r.Reason, ellipsis), keep) + ellipsis
+		if keep <= 0 {
+			r.Reason = ""
+		}
+		body, err = json.Marshal(r)
+	}
+	if err == nil && len(body) > maxResponseSize {
+		log.Printf("response of %d bytes exceeds the CloudFormation limit of %d bytes, sending status failed\n", len(body), maxResponseSize)
+		r.Status = StatusFailed
+		r.Data = nil
+		r.Reason = fmt.Sprintf("Response exceeds the CloudFormation limit of %d bytes, reduce the size of Data", maxResponseSize)
+		body, err = json.Marshal(r)
+	}
+	return body, err
+}
+
+// truncateUTF8 shortens s to at most n bytes, without splitting a multi-byte character.
+func truncateUTF8(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// send sends the response, retrying transient failures according to policy.
+// It stops retrying when ctx is done, or when the backoff would end after the deadline of ctx.
+func (r *Response) send(ctx context.Context, client HTTPClient, policy RetryPolicy) error {
+	body, err := r.marshal()
 	if err != nil {
 		return err
 	}
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		retryable, err := r.sendBody(ctx, client, body)
+		if err == nil || !retryable || attempt >= policy.MaxAttempts {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= backoff {
+			log.Printf("attempt %d to send response failed, no time left to retry before the deadline: %v\n", attempt, err)
+			return err
+		}
+		log.Printf("attempt %d to send response failed, retrying in %v: %v\n", attempt, backoff, err)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
 
-	req, err := http.NewRequest(http.MethodPut, r.url, bytes.NewBuffer(body))
+// sendBody makes one attempt to PUT the body, and reports whether a failure is worth retrying.
+func (r *Response) sendBody(ctx context.Context, client HTTPClient, body []byte) (retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, r.url, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return false, err
 	}
 	req.Header.Del("Content-Type")
 
 	res, err := client.Do(req)
 	if err != nil {
-		return err
+		return true, err
 	}
 
 	body, err = ioutil.ReadAll(res.Body)
+	res.Body.Close()
 	if err != nil {
-		return err
+		return true, err
 	}
-	res.Body.Close()
 
 	if res.StatusCode != 200 {
 		log.Printf("StatusCode: %d\nBody: %v\n", res.StatusCode, string(body))
-		return fmt.Errorf("invalid status code. got: %d", res.StatusCode)
+		return res.StatusCode >= 500, fmt.Errorf("invalid status code. got: %d", res.StatusCode)
 	}
 
-	return nil
-
+	return false, nil
 }
 
 // Send will send the Response to the given URL using the
-// default HTTP client
+// default HTTP client and DefaultRetryPolicy
 func (r *Response) Send() error {
-	return r.sendWith(http.DefaultClient)
+	return r.SendWithContext(context.Background())
+}
+
+// SendWithContext sends the Response to the given URL, using the default HTTP client and
+// DefaultRetryPolicy unless overridden by WithHTTPClient or WithRetry. Retries stop when ctx is done.
+// The Reason is truncated to keep the response within the 4096 byte limit of CloudFormation.
+func (r *Response) SendWithContext(ctx context.Context, options ...Option) error {
+	o := newOptions(options...)
+	return r.send(ctx, o.client, o.retry)
 }
@@ -4,12 +4,21 @@ package cfn
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil" //nolint: staticcheck
+	"math"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDataCopiedFromRequest(t *testing.T) {
@@ -60,7 +69,7 @@ func TestRequestSentCorrectly(t *testing.T) {
 		},
 	}
 
-	assert.NoError(t, r.sendWith(client))
+	assert.NoError(t, r.send(context.Background(), client, RetryPolicy{MaxAttempts: 1}))
 }
 
 func TestRequestForbidden(t *testing.T) {
@@ -80,8 +89,119 @@ func TestRequestForbidden(t *testing.T) {
 		},
 	}
 
-	s := r.sendWith(client)
+	s := r.send(context.Background(), client, RetryPolicy{MaxAttempts: 1})
 	if assert.Error(t, s) {
 		assert.Equal(t, fmt.Errorf("invalid status code. got: %d", sc), s)
 	}
 }
+
+func TestSendRetries(t *testing.T) {
+	tests := map[string]struct {
+		responses     []int
+		networkErrors int
+		wantAttempts  int
+		wantErr       bool
+	}{
+		"succeeds first time":      {responses: []int{200}, wantAttempts: 1},
+		"retries server errors":    {responses: []int{500, 503, 200}, wantAttempts: 3},
+		"retries network errors":   {networkErrors: 2, responses: []int{200}, wantAttempts: 3},
+		"does not retry forbidden": {responses: []int{403, 200}, wantAttempts: 1, wantErr: true},
+		"gives up after max tries": {responses: []int{500, 500, 500, 500}, wantAttempts: 3, wantErr: true},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			attempts := 0
+			client := &mockClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					attempts++
+					if attempts <= test.networkErrors {
+						return nil, errors.New("connection reset")
+					}
+					return &http.Response{
+						StatusCode: test.responses[attempts-test.networkErrors-1],
+						Body:       nopCloser{bytes.NewBufferString("")},
+					}, nil
+				},
+			}
+			r := &Response{Status: StatusSuccess, url: "http://pre-signed-S3-url-for-response"}
+			err := r.SendWithContext(context.Background(), WithHTTPClient(client), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}))
+			assert.Equal(t, test.wantAttempts, attempts)
+			assert.Equal(t, test.wantErr, err != nil)
+		})
+	}
+}
+
+func TestSendStopsRetryingWhenContextIsDone(t *testing.T) {
+	attempts := 0
+	ctx, cancel := context.WithCancel(context.Background())
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			attempts++
+			cancel()
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: nopCloser{bytes.NewBufferString("")}}, nil
+		},
+	}
+	r := &Response{Status: StatusSuccess, url: "http://pre-signed-S3-url-for-response"}
+	start := time.Now()
+	err := r.SendWithContext(ctx, WithHTTPClient(client), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Minute, MaxBackoff: time.Minute}))
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+func TestSendDoesNotRetryPastTheDeadline(t *testing.T) {
+	attempts := 0
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return &http.Response{StatusCode: http.StatusInternalServerError, Body: nopCloser{bytes.NewBufferString("")}}, nil
+		},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	r := &Response{Status: StatusSuccess, url: "http://pre-signed-S3-url-for-response"}
+	start := time.Now()
+	err := r.SendWithContext(ctx, WithHTTPClient(client), WithRetry(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, MaxBackoff: time.Hour}))
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Less(t, time.Since(start), time.Minute)
+}
+
+func TestSendTruncatesLargeResponses(t *testing.T) {
+	var sent Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			body, err := ioutil.ReadAll(req.Body)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(body), maxResponseSize)
+			require.NoError(t, json.Unmarshal(body, &sent))
+			return &http.Response{StatusCode: http.StatusOK, Body: nopCloser{bytes.NewBufferString("")}}, nil
+		},
+	}
+
+	r := &Response{Status: StatusFailed, Reason: strings.Repeat("é", 3000), url: "http://pre-signed-S3-url-for-response"}
+	require.NoError(t, r.send(context.Background(), client, RetryPolicy{MaxAttempts: 1}))
+	assert.Equal(t, StatusFailed, sent.Status)
+	assert.True(t, strings.HasSuffix(sent.Reason, "..."))
+	assert.True(t, utf8.ValidString(sent.Reason))
+
+	r = &Response{Status: StatusSuccess, Data: map[string]interface{}{"Big": strings.Repeat("x", 5000)}, url: "http://pre-signed-S3-url-for-response"}
+	require.NoError(t, r.send(context.Background(), client, RetryPolicy{MaxAttempts: 1}))
+	assert.Equal(t, StatusFailed, sent.Status)
+	assert.Nil(t, sent.Data)
+	assert.Contains(t, sent.Reason, "4096 bytes")
+	assert.Equal(t, StatusSuccess, r.Status, "the response must not be changed by sending it")
+	assert.Len(t, r.Data, 1)
+}
+
+func TestSetData(t *testing.T) {
+	r := &Response{}
+	assert.NoError(t, r.SetData("Name", "bucket"))
+	assert.NoError(t, r.SetData("Count", 3))
+	assert.NoError(t, r.SetData("Zones", []string{"a", "b"}))
+	assert.NoError(t, r.SetData("Tags", map[string]interface{}{"env": "prod", "ratio": 0.5}))
+	assert.NoError(t, r.SetData("Ports", map[int]string{80: "http"}))
+	assert.EqualError(t, r.SetData("Ratio", math.NaN()), "cfn: Data value Ratio cannot be encoded: json: unsupported value: NaN")
+	assert.EqualError(t, r.SetData("Events", make(chan int)), "cfn: Data value Events cannot be encoded: json: unsupported type: chan int")
+	assert.Len(t, r.Data, 5)
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// maxPhysicalResourceIDSize is the longest PhysicalResourceId accepted by CloudFormation.
+const maxPhysicalResourceIDSize = 1024
+
+type responseContextKey struct{}
+
+// ResponseFromContext returns the Response that LambdaWrap will send for the current request.
+// A CustomResourceFunction can use it to set fields that have no return value, such as NoEcho.
+// Data returned by the function is merged into the Data of the Response, replacing keys set with SetData.
+func ResponseFromContext(ctx context.Context) (*Response, bool) {
+	r, ok := ctx.Value(responseContextKey{}).(*Response)
+	return r, ok
+}
+
+// SetNoEcho controls whether the Data of the response is masked when retrieved with Fn::GetAtt.
+func (r *Response) SetNoEcho(noEcho bool) {
+	r.NoEcho = noEcho
+}
+
+// SetData sets a value of the Data of the response.
+// The value is sent the way json.Marshal encodes it, and SetData fails for values that it cannot encode.
+func (r *Response) SetData(key string, value interface{}) error {
+	if err := validateDataValue(key, value); err != nil {
+		return err
+	}
+	if r.Data == nil {
+		r.Data = make(map[string]interface{})
+	}
+	r.Data[key] = value
+	return nil
+}
+
+// validateResponse checks the rules CloudFormation applies to a response,
+// so that a misconfigured resource fails with a clear reason instead of being rejected by CloudFormation.
+func validateResponse(r *Response) error {
+	if len(r.PhysicalResourceID) > maxPhysicalResourceIDSize {
+		return fmt.Errorf("cfn: PhysicalResourceId is %d bytes, the limit is %d bytes", len(r.PhysicalResourceID), maxPhysicalResourceIDSize)
+	}
+	keys := make([]string, 0, len(r.Data))
+	for key := range r.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if err := validateDataValue(key, r.Data[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateDataValue checks a value of the Data of a response. Values are sent the way json.Marshal encodes them,
+// so only values that it cannot encode are rejected.
+func validateDataValue(key string, value interface{}) error {
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Errorf("cfn: Data value %s cannot be encoded: %v", key, err)
+	}
+	return nil
+}
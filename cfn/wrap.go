@@ -8,6 +8,8 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/aws/aws-lambda-go/events"
 )
@@ -24,7 +26,9 @@ type SNSCustomResourceLambdaFunction func(context.Context, events.SNSEvent) (rea
 // to CloudFormation.
 type CustomResourceFunction func(context.Context, Event) (physicalResourceID string, data map[string]interface{}, err error)
 
-func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client httpClient) (fn CustomResourceLambdaFunction) {
+func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client HTTPClient, options ...Option) (fn CustomResourceLambdaFunction) {
+	o := newOptions(append([]Option{WithHTTPClient(client)}, options...)...)
+
 	fn = func(ctx context.Context, event Event) (reason string, err error) {
 		r := NewResponse(&event)
 
@@ -35,6 +39,47 @@ func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client httpClie
 			fallbackPhysicalResourceID = event.RequestID
 		}
 
+		// CloudFormation only accepts the first response, so the function result, a panic, and a timeout race to send it.
+		var once sync.Once
+		respond := func(send func() error) (sent bool, err error) {
+			once.Do(func() {
+				sent = true
+				err = send()
+			})
+			return sent, err
+		}
+
+		// Responses are sent with the context of the invoke, which outlives the deadline given to the function.
+		invokeCtx := ctx
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout := deadline.Add(-o.timeoutMargin)
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithDeadline(ctx, timeout)
+			defer cancel()
+			timer := time.AfterFunc(time.Until(timeout), func() {
+				_, err := respond(func() error {
+					if o.continuation != nil {
+						err := o.continuation(invokeCtx, event)
+						if err == nil {
+							log.Printf("function timed out, continuing in another invoke\n")
+							return nil
+						}
+						log.Printf("continuation failed: %v\n", err)
+					}
+					r := NewResponse(&event)
+					r.Status = StatusFailed
+					r.Reason = "Function timed out, see log stream for details"
+					r.PhysicalResourceID = fallbackPhysicalResourceID
+					log.Printf("sending status failed: %s\n", r.Reason)
+					return r.send(invokeCtx, o.client, o.retry)
+				})
+				if err != nil {
+					log.Printf("failed to send timeout response: %v\n", err)
+				}
+			})
+			defer timer.Stop()
+		}
+
 		funcDidPanic := true
 		defer func() {
 			if funcDidPanic {
@@ -42,17 +87,43 @@ func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client httpClie
 				r.Reason = "Function panicked, see log stream for details"
 				r.PhysicalResourceID = fallbackPhysicalResourceID
 				// FIXME: something should be done if an error is returned here
-				_ = r.sendWith(client)
+				_, _ = respond(func() error { return r.send(invokeCtx, o.client, o.retry) })
 			}
 		}()
 
-		r.PhysicalResourceID, r.Data, err = lambdaFunction(ctx, event)
+		var data map[string]interface{}
+		r.PhysicalResourceID, data, err = lambdaFunction(context.WithValue(ctx, responseContextKey{}, r), event)
 		funcDidPanic = false
 
 		if r.PhysicalResourceID == "" {
 			r.PhysicalResourceID = fallbackPhysicalResourceID
 			log.Printf("PhysicalResourceID not set. Using fallback PhysicalResourceID: %s\n", r.PhysicalResourceID)
 		}
+		if event.RequestType == RequestDelete && r.PhysicalResourceID != event.PhysicalResourceID {
+			log.Printf("PhysicalResourceID must not change on Delete, got %s. Using the original PhysicalResourceID: %s\n",
+				r.PhysicalResourceID, event.PhysicalResourceID)
+			r.PhysicalResourceID = event.PhysicalResourceID
+		}
+		for key, value := range data {
+			if r.Data == nil {
+				r.Data = make(map[string]interface{}, len(data))
+			}
+			r.Data[key] = value
+		}
+
+		if verr := validateResponse(r); verr != nil {
+			// CloudFormation rejects the whole response if the PhysicalResourceId is invalid, so fall back to one it accepts.
+			if len(r.PhysicalResourceID) > maxPhysicalResourceIDSize {
+				r.PhysicalResourceID = fallbackPhysicalResourceID
+			}
+			r.Data = nil
+			if err == nil {
+				err = verr
+			}
+		} else if err == nil && event.RequestType == RequestUpdate && r.PhysicalResourceID != event.PhysicalResourceID {
+			log.Printf("PhysicalResourceID changed from %s to %s. CloudFormation will replace the resource and send a Delete request for %s\n",
+				event.PhysicalResourceID, r.PhysicalResourceID, event.PhysicalResourceID)
+		}
 
 		if err != nil {
 			r.Status = StatusFailed
@@ -62,7 +133,10 @@ func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client httpClie
 			r.Status = StatusSuccess
 		}
 
-		err = r.sendWith(client)
+		sent, err := respond(func() error { return r.send(invokeCtx, o.client, o.retry) })
+		if !sent {
+			log.Printf("response already sent after timeout, discarding status %s\n", r.Status)
+		}
 		if err != nil {
 			reason = err.Error()
 		}
@@ -86,14 +160,14 @@ func lambdaWrapWithClient(lambdaFunction CustomResourceFunction, client httpClie
 //	func main() {
 //		lambda.Start(cfn.LambdaWrap(myLambda))
 //	}
-func LambdaWrap(lambdaFunction CustomResourceFunction) (fn CustomResourceLambdaFunction) {
-	return lambdaWrapWithClient(lambdaFunction, http.DefaultClient)
+func LambdaWrap(lambdaFunction CustomResourceFunction, options ...Option) (fn CustomResourceLambdaFunction) {
+	return lambdaWrapWithClient(lambdaFunction, http.DefaultClient, options...)
 }
 
 // LambdaWrapSNS wraps a Lambda handler with support for SNS-based custom
 // resources. Usage and purpose otherwise same as LambdaWrap().
-func LambdaWrapSNS(lambdaFunction CustomResourceFunction) SNSCustomResourceLambdaFunction {
-	inner := LambdaWrap(lambdaFunction)
+func LambdaWrapSNS(lambdaFunction CustomResourceFunction, options ...Option) SNSCustomResourceLambdaFunction {
+	inner := LambdaWrap(lambdaFunction, options...)
 	return func(ctx context.Context, event events.SNSEvent) (reason string, err error) {
 		if len(event.Records) != 1 {
 			err = errors.New("expected exactly 1 incoming record")
@@ -0,0 +1,70 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"context"
+	"fmt"
+)
+
+// Resource is a Custom Resource with properties decoded into P. See LambdaWrapTyped.
+// Each method returns the same values as a CustomResourceFunction.
+type Resource[P any] interface {
+	Create(ctx context.Context, event Event, properties P) (physicalResourceID string, data map[string]interface{}, err error)
+	Update(ctx context.Context, event Event, properties, oldProperties P) (physicalResourceID string, data map[string]interface{}, err error)
+	Delete(ctx context.Context, event Event, properties P) (physicalResourceID string, data map[string]interface{}, err error)
+}
+
+// LambdaWrapTyped is the same as LambdaWrap, except that the resource properties are decoded into P with
+// DecodeProperties, and each request type is dispatched to the matching method of the resource.
+// A failure to decode the properties of a Create or an Update is reported to CloudFormation as a failure
+// of the request, see TypedFunction.
+//
+//	type BucketProperties struct {
+//		Name      string
+//		Versioned bool
+//		MaxKeys   int
+//	}
+//
+//	type bucket struct{}
+//
+//	func (bucket) Create(ctx context.Context, event cfn.Event, props BucketProperties) (string, map[string]interface{}, error) {...}
+//	func (bucket) Update(ctx context.Context, event cfn.Event, props, oldProps BucketProperties) (string, map[string]interface{}, error) {...}
+//	func (bucket) Delete(ctx context.Context, event cfn.Event, props BucketProperties) (string, map[string]interface{}, error) {...}
+//
+//	func main() {
+//		lambda.Start(cfn.LambdaWrapTyped[BucketProperties](bucket{}))
+//	}
+func LambdaWrapTyped[P any](resource Resource[P], options ...Option) CustomResourceLambdaFunction {
+	return LambdaWrap(TypedFunction(resource), options...)
+}
+
+// TypedFunction adapts a Resource into a CustomResourceFunction, for use with other wrappers such as LambdaWrapSNS.
+//
+// Only the properties of a Create or an Update fail the request when they can't be decoded. A Delete, and the old
+// properties of an Update, are sent again by CloudFormation when it rolls back a request that failed, so the
+// resource receives the zero value of P for them instead, and can still clean up using the PhysicalResourceID.
+func TypedFunction[P any](resource Resource[P]) CustomResourceFunction {
+	return func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+		var properties P
+		if event.RequestType == RequestDelete {
+			_ = DecodeProperties(event.ResourceProperties, &properties)
+			return resource.Delete(ctx, event, properties)
+		}
+		if err := DecodeProperties(event.ResourceProperties, &properties); err != nil {
+			return "", nil, err
+		}
+		switch event.RequestType {
+		case RequestCreate:
+			return resource.Create(ctx, event, properties)
+		case RequestUpdate:
+			var oldProperties P
+			_ = DecodeProperties(event.OldResourceProperties, &oldProperties)
+			return resource.Update(ctx, event, properties, oldProperties)
+		}
+		return "", nil, fmt.Errorf("cfn: unknown request type %q", event.RequestType)
+	}
+}
@@ -0,0 +1,123 @@
+//go:build go1.18
+// +build go1.18
+
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package cfn
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type bucketProperties struct {
+	Name      string
+	Versioned bool
+}
+
+type bucketResource struct {
+	calls []string
+}
+
+func (b *bucketResource) Create(ctx context.Context, event Event, properties bucketProperties) (string, map[string]interface{}, error) {
+	b.calls = append(b.calls, "create "+properties.Name)
+	return properties.Name, map[string]interface{}{"Versioned": properties.Versioned}, nil
+}
+
+func (b *bucketResource) Update(ctx context.Context, event Event, properties, oldProperties bucketProperties) (string, map[string]interface{}, error) {
+	b.calls = append(b.calls, "update "+oldProperties.Name+" to "+properties.Name)
+	return properties.Name, nil, nil
+}
+
+func (b *bucketResource) Delete(ctx context.Context, event Event, properties bucketProperties) (string, map[string]interface{}, error) {
+	b.calls = append(b.calls, "delete "+properties.Name)
+	return "", nil, nil
+}
+
+func TestTypedFunction(t *testing.T) {
+	var responses []Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			responses = append(responses, extractResponseBody(t, req))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+	resource := &bucketResource{}
+	fn := lambdaWrapWithClient(TypedFunction[bucketProperties](resource), client)
+
+	create := *testEvent
+	create.RequestType = RequestCreate
+	create.PhysicalResourceID = ""
+	create.ResourceProperties = map[string]interface{}{"Name": "a", "Versioned": "true"}
+	update := *testEvent
+	update.PhysicalResourceID = "a"
+	update.ResourceProperties = map[string]interface{}{"Name": "b", "Versioned": "false"}
+	update.OldResourceProperties = create.ResourceProperties
+	del := *testEvent
+	del.RequestType = RequestDelete
+	del.PhysicalResourceID = "b"
+	del.ResourceProperties = update.ResourceProperties
+	invalid := create
+	invalid.ResourceProperties = map[string]interface{}{"Versioned": "maybe"}
+
+	for _, event := range []Event{create, update, del, invalid} {
+		_, err := fn(context.TODO(), event)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"create a", "update a to b", "delete b"}, resource.calls)
+	assert.Len(t, responses, 4)
+	assert.Equal(t, StatusSuccess, responses[0].Status)
+	assert.Equal(t, "a", responses[0].PhysicalResourceID)
+	assert.Equal(t, map[string]interface{}{"Versioned": true}, responses[0].Data)
+	assert.Equal(t, "b", responses[1].PhysicalResourceID)
+	assert.Equal(t, "b", responses[2].PhysicalResourceID)
+	assert.Equal(t, StatusFailed, responses[3].Status)
+	assert.Equal(t, `cfn: property Versioned: cannot convert "maybe" to bool`, responses[3].Reason)
+}
+
+func TestTypedFunctionRollback(t *testing.T) {
+	var responses []Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			responses = append(responses, extractResponseBody(t, req))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+	resource := &bucketResource{}
+	fn := lambdaWrapWithClient(TypedFunction[bucketProperties](resource), client)
+
+	badCreate := *testEvent
+	badCreate.RequestType = RequestCreate
+	badCreate.PhysicalResourceID = ""
+	badCreate.ResourceProperties = map[string]interface{}{"Name": "a", "Versioned": "maybe"}
+	rollbackDelete := badCreate
+	rollbackDelete.RequestType = RequestDelete
+	rollbackDelete.PhysicalResourceID = "failed-create"
+	rollbackUpdate := *testEvent
+	rollbackUpdate.PhysicalResourceID = "a"
+	rollbackUpdate.ResourceProperties = map[string]interface{}{"Name": "a"}
+	rollbackUpdate.OldResourceProperties = badCreate.ResourceProperties
+
+	for _, event := range []Event{badCreate, rollbackDelete, rollbackUpdate} {
+		_, err := fn(context.TODO(), event)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"delete ", "update  to a"}, resource.calls)
+	assert.Len(t, responses, 3)
+	assert.Equal(t, StatusFailed, responses[0].Status)
+	assert.Equal(t, StatusSuccess, responses[1].Status)
+	assert.Equal(t, "failed-create", responses[1].PhysicalResourceID)
+	assert.Equal(t, StatusSuccess, responses[2].Status)
+}
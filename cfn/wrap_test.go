@@ -9,10 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil" //nolint: staticcheck
+	"math"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var testEvent = &Event{
@@ -62,11 +67,11 @@ func TestLambdaPhysicalResourceId(t *testing.T) {
 		{RequestDelete, fmt.Errorf("dummy error"), "prevPhysicalResourceID", "prevPhysicalResourceID"},
 
 		// For Delete with returned PhysicalResourceID != old PhysicalResourceID
-		// Technically, lambda handlers shouldn't return a different physical resource id upon deletion.
-		// Typescript CDK implementation for handlers would return an error here.
-		// But CFn ignores the returned PhysicalResourceID for deletion so it doesn't matter.
-		{RequestDelete, nil, "newPhysicalResourceID", "newPhysicalResourceID"},
-		{RequestDelete, fmt.Errorf("dummy error"), "newPhysicalResourceID", "newPhysicalResourceID"},
+		// Lambda handlers shouldn't return a different physical resource id upon deletion,
+		// and CFn fails a Delete response whose PhysicalResourceID changed,
+		// so the old PhysicalResourceID is sent instead and the change is only logged.
+		{RequestDelete, nil, "newPhysicalResourceID", "prevPhysicalResourceID"},
+		{RequestDelete, fmt.Errorf("dummy error"), "newPhysicalResourceID", "prevPhysicalResourceID"},
 	}
 	for _, test := range tests {
 
@@ -216,3 +221,225 @@ func extractResponseBody(t *testing.T, req *http.Request) Response {
 	assert.NoError(t, err)
 	return response
 }
+
+func TestTimeoutSendsFailureOnce(t *testing.T) {
+	for name, params := range map[string]struct {
+		continuation      func(context.Context, Event) error
+		expectResponses   int
+		expectContinued   bool
+		expectFailureSent bool
+	}{
+		"timeout": {
+			expectResponses:   1,
+			expectFailureSent: true,
+		},
+		"continuation": {
+			continuation:    func(context.Context, Event) error { return nil },
+			expectContinued: true,
+		},
+		"failed continuation": {
+			continuation:      func(context.Context, Event) error { return errors.New("no can do") },
+			expectResponses:   1,
+			expectContinued:   true,
+			expectFailureSent: true,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var mu sync.Mutex
+			var responses []Response
+			client := &mockClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					responses = append(responses, extractResponseBody(t, req))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       nopCloser{bytes.NewBufferString("")},
+					}, nil
+				},
+			}
+			continued := false
+			var options []Option
+			options = append(options, WithTimeoutMargin(20*time.Millisecond))
+			if params.continuation != nil {
+				options = append(options, WithContinuation(func(ctx context.Context, event Event) error {
+					continued = true
+					assert.Equal(t, testEvent.RequestID, event.RequestID)
+					return params.continuation(ctx, event)
+				}))
+			}
+
+			fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+				<-ctx.Done()
+				time.Sleep(10 * time.Millisecond) // give the timeout response a chance to win
+				return "too late", nil, nil
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			_, err := lambdaWrapWithClient(fn, client, options...)(ctx, *testEvent)
+			assert.NoError(t, err)
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Equal(t, params.expectContinued, continued)
+			assert.Len(t, responses, params.expectResponses)
+			if params.expectFailureSent {
+				assert.Equal(t, StatusFailed, responses[0].Status)
+				assert.Equal(t, "Function timed out, see log stream for details", responses[0].Reason)
+				assert.Equal(t, testEvent.PhysicalResourceID, responses[0].PhysicalResourceID)
+			}
+		})
+	}
+}
+
+func TestNoTimeoutWhenFunctionCompletes(t *testing.T) {
+	var responses []Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			responses = append(responses, extractResponseBody(t, req))
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+	fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+		return "done", nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := lambdaWrapWithClient(fn, client, WithTimeoutMargin(20*time.Millisecond))(ctx, *testEvent)
+	assert.NoError(t, err)
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Len(t, responses, 1)
+	assert.Equal(t, StatusSuccess, responses[0].Status)
+	assert.Equal(t, "done", responses[0].PhysicalResourceID)
+}
+
+func TestInvalidResponseFails(t *testing.T) {
+	tests := map[string]struct {
+		requestType                RequestType
+		physicalResourceID         string
+		data                       map[string]interface{}
+		expectedPhysicalResourceID string
+		expectedReason             string
+	}{
+		"PhysicalResourceID too long": {
+			requestType:                RequestUpdate,
+			physicalResourceID:         strings.Repeat("x", 1025),
+			expectedPhysicalResourceID: "prevPhysicalResourceID",
+			expectedReason:             "cfn: PhysicalResourceId is 1025 bytes, the limit is 1024 bytes",
+		},
+		"infinity in Data": {
+			requestType:                RequestUpdate,
+			physicalResourceID:         "newPhysicalResourceID",
+			data:                       map[string]interface{}{"Ratio": math.Inf(1)},
+			expectedPhysicalResourceID: "newPhysicalResourceID",
+			expectedReason:             "cfn: Data value Ratio cannot be encoded: json: unsupported value: +Inf",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			event := *testEvent
+			event.RequestType = test.requestType
+
+			var response Response
+			client := &mockClient{
+				DoFunc: func(req *http.Request) (*http.Response, error) {
+					response = extractResponseBody(t, req)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       nopCloser{bytes.NewBufferString("")},
+					}, nil
+				},
+			}
+
+			fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+				return test.physicalResourceID, test.data, nil
+			}
+
+			_, err := lambdaWrapWithClient(fn, client)(context.TODO(), event)
+			assert.NoError(t, err)
+			assert.Equal(t, StatusFailed, response.Status)
+			assert.Equal(t, test.expectedPhysicalResourceID, response.PhysicalResourceID)
+			assert.Equal(t, test.expectedReason, response.Reason)
+			assert.Nil(t, response.Data)
+		})
+	}
+}
+
+func TestDeleteKeepsPhysicalResourceID(t *testing.T) {
+	event := *testEvent
+	event.RequestType = RequestDelete
+
+	var response Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			response = extractResponseBody(t, req)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+
+	fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+		return "newPhysicalResourceID", nil, nil
+	}
+
+	_, err := lambdaWrapWithClient(fn, client)(context.TODO(), event)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSuccess, response.Status)
+	assert.Equal(t, "prevPhysicalResourceID", response.PhysicalResourceID)
+}
+
+func TestDataIsEncodedAsJSON(t *testing.T) {
+	var response Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			response = extractResponseBody(t, req)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+
+	fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+		return "", map[string]interface{}{"Endpoint": struct{ Host string }{"example.com"}, "Nothing": nil}, nil
+	}
+
+	_, err := lambdaWrapWithClient(fn, client)(context.TODO(), *testEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSuccess, response.Status)
+	assert.Equal(t, map[string]interface{}{"Endpoint": map[string]interface{}{"Host": "example.com"}, "Nothing": nil}, response.Data)
+}
+
+func TestResponseFromContext(t *testing.T) {
+	var response Response
+	client := &mockClient{
+		DoFunc: func(req *http.Request) (*http.Response, error) {
+			response = extractResponseBody(t, req)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       nopCloser{bytes.NewBufferString("")},
+			}, nil
+		},
+	}
+
+	fn := func(ctx context.Context, event Event) (physicalResourceID string, data map[string]interface{}, err error) {
+		r, ok := ResponseFromContext(ctx)
+		require.True(t, ok)
+		r.SetNoEcho(true)
+		require.NoError(t, r.SetData("Password", "hunter2"))
+		require.NoError(t, r.SetData("Port", 5432))
+		return "", map[string]interface{}{"Port": "5433", "Host": "db.example.com"}, nil
+	}
+
+	_, err := lambdaWrapWithClient(fn, client)(context.TODO(), *testEvent)
+	assert.NoError(t, err)
+	assert.Equal(t, StatusSuccess, response.Status)
+	assert.True(t, response.NoEcho)
+	assert.Equal(t, map[string]interface{}{"Password": "hunter2", "Port": "5433", "Host": "db.example.com"}, response.Data)
+}
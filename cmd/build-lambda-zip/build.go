@@ -0,0 +1,194 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil" //nolint: staticcheck
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const buildUsage = `build-lambda-zip build - Builds Go packages for AWS Lambda and puts each executable into a zip file as bootstrap.
+usage:
+  build-lambda-zip build [options] packages...
+options:
+  --arch            the Lambda architecture to build for, amd64 or arm64. (default: amd64)
+  -o, --output      output file path for the zip, when building a single package. (default: ${package}.zip)
+  --output-dir      directory for the zips of the packages, each named after its package. (default: .)
+  --include         a path to add to every zip, in the same forms as the paths of build-lambda-zip. May be repeated.
+  -j, --jobs        how many packages to build in parallel. (default: the number of CPUs)
+  --reproducible    produce a byte-identical zip for identical inputs. (default: true)
+  --ignore-file     file of .gitignore style patterns to exclude. (default: .lambdaignore)
+  -h, --help        prints usage
+The packages are built with GOOS=linux, CGO_ENABLED=0, -tags lambda.norpc and -trimpath, for the provided.al2023 runtime.
+`
+
+// buildOptions configures the build subcommand.
+type buildOptions struct {
+	arch      string
+	output    string
+	outputDir string
+	include   []string
+	jobs      int
+	zip       zipOptions
+}
+
+// stringsFlag is a flag that may be repeated.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringsFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// isBuildCommand reports whether the arguments run the build subcommand. A handler executable named build,
+// which could be zipped before the subcommand was added, takes precedence, so existing scripts keep working.
+// A directory named build, which many projects have, does not.
+func isBuildCommand(args []string) bool {
+	if len(args) == 0 || args[0] != "build" {
+		return false
+	}
+	info, err := os.Stat("build")
+	return err != nil || !info.Mode().IsRegular()
+}
+
+// buildMain runs the build subcommand with the arguments that follow it.
+func buildMain(args []string) error {
+	var opts buildOptions
+	var include stringsFlag
+	var reproducible bool
+	var ignoreFile string
+	flags := flag.NewFlagSet("build", flag.ExitOnError)
+	flags.StringVar(&opts.arch, "arch", "amd64", "")
+	flags.StringVar(&opts.output, "o", "", "")
+	flags.StringVar(&opts.output, "output", "", "")
+	flags.StringVar(&opts.outputDir, "output-dir", ".", "")
+	flags.Var(&include, "include", "")
+	flags.IntVar(&opts.jobs, "j", runtime.NumCPU(), "")
+	flags.IntVar(&opts.jobs, "jobs", runtime.NumCPU(), "")
+	flags.BoolVar(&reproducible, "reproducible", true, "")
+	flags.StringVar(&ignoreFile, "ignore-file", ".lambdaignore", "")
+	flags.Usage = func() {
+		fmt.Fprint(os.Stderr, buildUsage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		return fmt.Errorf("no packages provided")
+	}
+	opts.include = include
+
+	var err error
+	if opts.zip, err = newZipOptions(reproducible); err != nil {
+		return err
+	}
+	if opts.zip.ignore, err = loadIgnoreFile(ignoreFile); err != nil {
+		return fmt.Errorf("failed to read ignore file: %v", err)
+	}
+	return buildFunctions(flags.Args(), opts)
+}
+
+// buildFunctions builds and zips each of the packages, opts.jobs at a time.
+func buildFunctions(packages []string, opts buildOptions) error {
+	if opts.arch != "amd64" && opts.arch != "arm64" {
+		return fmt.Errorf("unsupported architecture %q, must be amd64 or arm64", opts.arch)
+	}
+	if opts.output != "" && len(packages) > 1 {
+		return fmt.Errorf("--output can only be used when building a single package, use --output-dir instead")
+	}
+	if opts.jobs < 1 {
+		opts.jobs = 1
+	}
+
+	outputs := make([]string, len(packages))
+	seen := make(map[string]string, len(packages))
+	for i, pkg := range packages {
+		name, err := packageName(pkg)
+		if err != nil {
+			return err
+		}
+		if other, ok := seen[name]; ok {
+			return fmt.Errorf("packages %s and %s would both be written to %s.zip", other, pkg, name)
+		}
+		seen[name] = pkg
+		outputs[i] = filepath.Join(opts.outputDir, name+".zip")
+	}
+	if opts.output != "" {
+		outputs[0] = opts.output
+	}
+
+	errs := make([]error, len(packages))
+	sem := make(chan struct{}, opts.jobs)
+	var wg sync.WaitGroup
+	for i := range packages {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = buildFunction(packages[i], outputs[i], opts)
+		}(i)
+	}
+	wg.Wait()
+
+	var failed []string
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", packages[i], err))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to build %d of %d packages:\n%s", len(failed), len(packages), strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// packageName returns the name of the zip for a package path, such as fn for ./cmd/fn or main.go.
+func packageName(pkg string) (string, error) {
+	if strings.Contains(pkg, "...") {
+		return "", fmt.Errorf("package patterns are not supported, list each package: %s", pkg)
+	}
+	abs, err := filepath.Abs(pkg)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(filepath.Base(abs), ".go"), nil
+}
+
+// goBuildCommand returns the command that builds pkg into a Lambda executable at exePath.
+func goBuildCommand(pkg, exePath, arch string) *exec.Cmd {
+	cmd := exec.Command("go", "build", "-o", exePath, "-tags", "lambda.norpc", "-trimpath", pkg)
+	cmd.Env = append(os.Environ(), "GOOS=linux", "GOARCH="+arch, "CGO_ENABLED=0")
+	return cmd
+}
+
+// buildFunction builds pkg as bootstrap and writes it, with the included paths, to the zip at outZipPath.
+func buildFunction(pkg, outZipPath string, opts buildOptions) error {
+	dir, err := ioutil.TempDir("", "build-lambda-zip")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	exePath := filepath.Join(dir, "bootstrap")
+	if out, err := goBuildCommand(pkg, exePath, opts.arch).CombinedOutput(); err != nil {
+		return fmt.Errorf("go build: %v\n%s", err, out)
+	}
+	if err := compressExeAndArgs(outZipPath, exePath, opts.include, opts.zip); err != nil {
+		return err
+	}
+	log.Printf("wrote %s", outZipPath)
+	return nil
+}
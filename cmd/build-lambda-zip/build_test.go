@@ -0,0 +1,87 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/zip"
+	"debug/elf"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFunctions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+		return
+	}
+	outputDir := t.TempDir()
+	opts := buildOptions{
+		arch:      "arm64",
+		outputDir: outputDir,
+		include:   []string{"testdata/noop.go=src/noop.go"},
+		jobs:      2,
+		zip:       zipOptions{reproducible: true, modTime: defaultModTime},
+	}
+	require.NoError(t, buildFunctions([]string{"testdata/noop.go", "testdata/apigw.go"}, opts))
+
+	for _, name := range []string{"noop", "apigw"} {
+		zipReader, err := zip.OpenReader(filepath.Join(outputDir, name+".zip"))
+		require.NoError(t, err)
+		defer zipReader.Close()
+
+		require.Len(t, zipReader.File, 2)
+		assert.Equal(t, "bootstrap", zipReader.File[0].Name)
+		assert.Equal(t, os.FileMode(0755), zipReader.File[0].Mode())
+		assert.Equal(t, "src/noop.go", zipReader.File[1].Name)
+
+		f, err := zipReader.File[0].Open()
+		require.NoError(t, err)
+		exePath := filepath.Join(t.TempDir(), "bootstrap")
+		data, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(exePath, data, 0755))
+		exe, err := elf.Open(exePath)
+		require.NoError(t, err)
+		assert.Equal(t, elf.EM_AARCH64, exe.Machine)
+		exe.Close()
+	}
+}
+
+func TestBuildFunctionsValidation(t *testing.T) {
+	opts := buildOptions{arch: "amd64", outputDir: t.TempDir(), jobs: 1}
+
+	err := buildFunctions([]string{"./a/fn", "./b/fn"}, opts)
+	assert.EqualError(t, err, "packages ./a/fn and ./b/fn would both be written to fn.zip")
+
+	assert.Error(t, buildFunctions([]string{"./..."}, opts))
+
+	opts.output = "fn.zip"
+	assert.Error(t, buildFunctions([]string{"./a", "./b"}, opts))
+
+	opts.arch = "386"
+	assert.Error(t, buildFunctions([]string{"./a"}, opts))
+}
+
+func TestIsBuildCommand(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	dir := t.TempDir()
+	require.NoError(t, os.Chdir(dir))
+	defer func() { require.NoError(t, os.Chdir(wd)) }()
+
+	assert.False(t, isBuildCommand(nil))
+	assert.False(t, isBuildCommand([]string{"bootstrap"}))
+	assert.True(t, isBuildCommand([]string{"build", "./cmd/fn"}))
+
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "build"), 0755))
+	assert.True(t, isBuildCommand([]string{"build", "./cmd/fn"}), "a build directory is not a handler executable")
+	require.NoError(t, os.Remove(filepath.Join(dir, "build")))
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "build"), []byte("not really an exe"), 0755))
+	assert.False(t, isBuildCommand([]string{"build"}), "a handler executable named build is zipped")
+}
@@ -0,0 +1,292 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// collectEntries expands the paths given after the handler executable into entries.
+// Each path is a file, a directory or a glob pattern, optionally followed by =dest to set where it is put in the zip,
+// see splitPathArg.
+// A file is stored as dest, while the contents of a directory, and the files matched by a glob, are stored under dest.
+// Files found by walking a directory or matching a glob are skipped if their path in the zip matches opts.ignore.
+// Absolute paths without =dest are stored without their leading slash. It is an error for a path in the zip to be
+// absolute or to start with "..", since it would be extracted outside of the zip root.
+func collectEntries(args []string, opts zipOptions) ([]entry, error) {
+	var entries []entry
+	for _, arg := range args {
+		src, dest, glob := splitPathArg(arg)
+		if !glob {
+			name := dest
+			if name == "" {
+				name = sourceName(src)
+			}
+			name, err := zipName(name, arg)
+			if err != nil {
+				return nil, err
+			}
+			e, err := collectPath(src, name, opts)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e...)
+			continue
+		}
+
+		matches, err := filepath.Glob(src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %v", src, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match %s", src)
+		}
+		for _, match := range matches {
+			info, err := os.Stat(match)
+			if err != nil {
+				return nil, err
+			}
+			name := sourceName(match)
+			if dest != "" {
+				name = path.Join(dest, filepath.Base(match))
+			}
+			if name, err = zipName(name, arg); err != nil {
+				return nil, err
+			}
+			if opts.ignore.match(name, info.IsDir()) {
+				continue
+			}
+			e, err := collectPath(match, name, opts)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e...)
+		}
+	}
+	return entries, nil
+}
+
+// sourceName returns the slash separated path in the zip of a source path given without =dest.
+// Absolute paths are stored relative to the root of the file system, so that they stay inside of the zip root.
+func sourceName(src string) string {
+	return strings.TrimLeft(filepath.ToSlash(strings.TrimPrefix(src, filepath.VolumeName(src))), "/")
+}
+
+// zipName cleans the slash separated path name, and returns an error if it is outside of the zip root.
+func zipName(name, arg string) (string, error) {
+	name = path.Clean(name)
+	if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+		return "", fmt.Errorf("%s would be stored as %s, outside of the zip root. Use =dest to choose where it goes", arg, name)
+	}
+	return name, nil
+}
+
+// splitPathArg splits a path argument into its source and its cleaned =dest, which is empty if there is none,
+// and reports whether the source is a glob pattern.
+// An argument that names an existing file is taken as it is, so that files with = or glob characters
+// in their name, such as assets/a=b.txt or routes/[id].js, keep working.
+func splitPathArg(arg string) (src, dest string, glob bool) {
+	if _, err := os.Lstat(arg); err == nil {
+		return arg, "", false
+	}
+	src = arg
+	if i := strings.LastIndex(arg, "="); i >= 0 {
+		src, dest = arg[:i], path.Clean(filepath.ToSlash(arg[i+1:]))
+		if _, err := os.Lstat(src); err == nil {
+			return src, dest, false
+		}
+	}
+	return src, dest, hasGlobMeta(src)
+}
+
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
+
+// collectPath returns the entries for a file, or for the contents of a directory.
+func collectPath(src, name string, opts zipOptions) ([]entry, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return walkDir(src, name, opts, map[string]bool{})
+	}
+	e, err := fileEntry(name, src, false, opts)
+	if err != nil {
+		return nil, err
+	}
+	return []entry{e}, nil
+}
+
+// walkDir returns the entries for the contents of the directory root, stored under name.
+// Relative symlinks that stay within root are kept as symlinks, while other symlinks are
+// followed, because their target will not exist once the function is deployed.
+// visited holds the directories already walked, to stop symlinks from looping.
+func walkDir(root, name string, opts zipOptions, visited map[string]bool) ([]entry, error) {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return nil, err
+	}
+	if visited[realRoot] {
+		return nil, fmt.Errorf("symlink loop at %s", root)
+	}
+	visited[realRoot] = true
+	defer delete(visited, realRoot)
+
+	var entries []entry
+	err = filepath.Walk(realRoot, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(realRoot, p)
+		if err != nil || rel == "." {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			if !filepath.IsAbs(target) && within(realRoot, filepath.Join(filepath.Dir(p), target)) {
+				if !opts.ignore.match(entryName, false) {
+					entries = append(entries, symlinkEntry(entryName, filepath.ToSlash(target), opts))
+				}
+				return nil
+			}
+			if info, err = os.Stat(p); err != nil {
+				return err
+			}
+			if opts.ignore.match(entryName, info.IsDir()) {
+				return nil
+			}
+			if info.IsDir() {
+				linked, err := walkDir(p, entryName, opts, visited)
+				entries = append(entries, linked...)
+				return err
+			}
+		}
+
+		if opts.ignore.match(entryName, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			return nil
+		}
+		e, err := fileEntry(entryName, p, false, opts)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// within reports whether the path p is inside the directory root.
+func within(root, p string) bool {
+	rel, err := filepath.Rel(root, p)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ignoreRules are the patterns of a .lambdaignore file. The format is a subset of .gitignore:
+// blank lines and lines starting with # are skipped, a leading ! re-includes a path excluded by an earlier
+// pattern, a trailing / only matches directories, and a pattern containing a / is matched against the whole
+// path rather than against any of its trailing elements. Patterns use the syntax of path.Match.
+type ignoreRules []ignoreRule
+
+type ignoreRule struct {
+	pattern  string
+	negate   bool
+	dirOnly  bool
+	anchored bool
+}
+
+// loadIgnoreFile reads the ignore rules from the file at path. A missing file has no rules.
+func loadIgnoreFile(path string) (ignoreRules, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return parseIgnoreRules(f)
+}
+
+func parseIgnoreRules(r io.Reader) (ignoreRules, error) {
+	var rules ignoreRules
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		rule.anchored = strings.Contains(line, "/")
+		rule.pattern = strings.TrimPrefix(line, "/")
+		if _, err := path.Match(rule.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %v", line, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, scanner.Err()
+}
+
+// match reports whether the slash separated path name, or any of the directories containing it, is ignored.
+func (rules ignoreRules) match(name string, isDir bool) bool {
+	elems := strings.Split(path.Clean(name), "/")
+	for i := 1; i < len(elems); i++ {
+		if rules.matchPath(strings.Join(elems[:i], "/"), true) {
+			return true
+		}
+	}
+	return rules.matchPath(strings.Join(elems, "/"), isDir)
+}
+
+func (rules ignoreRules) matchPath(name string, isDir bool) bool {
+	ignored := false
+	for _, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matches(name) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (rule ignoreRule) matches(name string) bool {
+	if rule.anchored {
+		ok, _ := path.Match(rule.pattern, name)
+		return ok
+	}
+	elems := strings.Split(name, "/")
+	for i := range elems {
+		if ok, _ := path.Match(rule.pattern, strings.Join(elems[i:], "/")); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTree(t *testing.T, root string, files map[string]os.FileMode) {
+	for name, mode := range files {
+		p := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(name), mode))
+	}
+}
+
+func entryModes(entries []entry) map[string]os.FileMode {
+	modes := make(map[string]os.FileMode, len(entries))
+	for _, e := range entries {
+		modes[e.name] = e.mode
+	}
+	return modes
+}
+
+func TestCollectEntries(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	writeTree(t, root, map[string]os.FileMode{
+		"static/index.html":        0644,
+		"static/app.js":            0644,
+		"static/app.js.map":        0644,
+		"static/tmp/scratch.txt":   0644,
+		"scripts/run.sh":           0700,
+		"config/prod.json":         0600,
+		"certs/ca.pem":             0644,
+		"certs/server.pem":         0644,
+		"certs/private/server.key": 0600,
+	})
+	writeTree(t, outside, map[string]os.FileMode{"shared.txt": 0644})
+	require.NoError(t, os.Symlink("index.html", filepath.Join(root, "static/home.html")))
+	require.NoError(t, os.Symlink(filepath.Join(outside, "shared.txt"), filepath.Join(root, "static/shared.txt")))
+	require.NoError(t, os.Symlink(outside, filepath.Join(root, "static/linked")))
+
+	ignore, err := parseIgnoreRules(strings.NewReader("# generated\n*.map\ntmp/\nprivate/\n"))
+	require.NoError(t, err)
+	opts := zipOptions{reproducible: true, modTime: defaultModTime, ignore: ignore}
+
+	entries, err := collectEntries([]string{
+		filepath.Join(root, "static") + "=public",
+		filepath.Join(root, "scripts", "run.sh") + "=bin/run",
+		filepath.Join(root, "config", "prod.json") + "=config.json",
+		filepath.Join(root, "certs", "*") + "=certs",
+	}, opts)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]os.FileMode{
+		"public/index.html":        0644,
+		"public/app.js":            0644,
+		"public/home.html":         0755 | os.ModeSymlink,
+		"public/shared.txt":        0644,
+		"public/linked/shared.txt": 0644,
+		"bin/run":                  0755,
+		"config.json":              0644,
+		"certs/ca.pem":             0644,
+		"certs/server.pem":         0644,
+	}, entryModes(entries))
+	for _, e := range entries {
+		if e.name == "public/home.html" {
+			assert.Equal(t, "index.html", e.link)
+		}
+	}
+
+	t.Run("paths without a destination keep their path", func(t *testing.T) {
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(root))
+		defer func() { require.NoError(t, os.Chdir(wd)) }()
+		entries, err := collectEntries([]string{"./config"}, zipOptions{reproducible: true, modTime: defaultModTime})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, "config/prod.json", entries[0].name)
+	})
+
+	t.Run("absolute paths without a destination", func(t *testing.T) {
+		entries, err := collectEntries([]string{filepath.Join(root, "config")}, zipOptions{reproducible: true, modTime: defaultModTime})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, strings.TrimPrefix(filepath.ToSlash(filepath.Join(root, "config", "prod.json")), "/"), entries[0].name)
+	})
+
+	t.Run("paths outside of the zip root", func(t *testing.T) {
+		for _, arg := range []string{
+			filepath.Join("..", filepath.Base(root), "config"),
+			filepath.Join(root, "config") + "=../config",
+			filepath.Join(root, "config") + "=/etc",
+			filepath.Join(root, "certs", "*") + "=certs/../../certs",
+		} {
+			_, err := collectEntries([]string{arg}, opts)
+			assert.Error(t, err, arg)
+		}
+	})
+
+	t.Run("existing paths with = or glob characters", func(t *testing.T) {
+		dir := t.TempDir()
+		writeTree(t, dir, map[string]os.FileMode{"assets/a=b.txt": 0644, "routes/[id].js": 0644, "routes/i.js": 0644})
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+		require.NoError(t, os.Chdir(dir))
+		defer func() { require.NoError(t, os.Chdir(wd)) }()
+		entries, err := collectEntries([]string{"assets/a=b.txt", "routes/[id].js", "assets/a=b.txt=copy.txt", "routes/[id].js=page.js"}, zipOptions{reproducible: true, modTime: defaultModTime})
+		require.NoError(t, err)
+		names := make([]string, len(entries))
+		for i, e := range entries {
+			names[i] = e.name
+		}
+		assert.Equal(t, []string{"assets/a=b.txt", "routes/[id].js", "copy.txt", "page.js"}, names)
+	})
+
+	t.Run("unmatched glob", func(t *testing.T) {
+		_, err := collectEntries([]string{filepath.Join(root, "*.yaml")}, opts)
+		assert.Error(t, err)
+	})
+
+	t.Run("symlink loop", func(t *testing.T) {
+		loop := t.TempDir()
+		require.NoError(t, os.Symlink(loop, filepath.Join(loop, "self")))
+		_, err := collectEntries([]string{loop + "=loop"}, opts)
+		assert.Error(t, err)
+	})
+}
+
+func TestIgnoreRules(t *testing.T) {
+	rules, err := parseIgnoreRules(strings.NewReader("*.log\n!keep.log\nbuild/\n/docs/*.md\n"))
+	require.NoError(t, err)
+	cases := map[string]struct {
+		isDir   bool
+		ignored bool
+	}{
+		"app.log":          {ignored: true},
+		"logs/app.log":     {ignored: true},
+		"logs/keep.log":    {ignored: false},
+		"build":            {isDir: true, ignored: true},
+		"src/build":        {isDir: false, ignored: false},
+		"src/build/out.go": {ignored: true},
+		"docs/README.md":   {ignored: true},
+		"src/docs/a.md":    {ignored: false},
+		"main.go":          {ignored: false},
+	}
+	for name, c := range cases {
+		assert.Equal(t, c.ignored, rules.match(name, c.isDir), name)
+	}
+
+	_, err = parseIgnoreRules(strings.NewReader("[\n"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,70 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// layerEntries returns the entries of a Lambda layer. Layers are extracted to /opt, where Lambda starts every
+// executable in /opt/extensions as an extension, so each extension is put in extensions/ with its executable bits.
+// Each extension is given as exe or exe=name. The paths are collected like those of a function,
+// except that a path without =dest is put in lib/ rather than at its own path.
+func layerEntries(extensions []string, paths []string, opts zipOptions) ([]entry, error) {
+	var entries []entry
+	names := make(map[string]string, len(extensions))
+	for _, extension := range extensions {
+		src, name := extension, filepath.Base(extension)
+		if i := strings.Index(extension, "="); i >= 0 {
+			src, name = extension[:i], extension[i+1:]
+		}
+		if name == "" || name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return nil, fmt.Errorf("invalid extension name %q", name)
+		}
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("extensions %s and %s are both named %s", other, src, name)
+		}
+		names[name] = src
+
+		e, err := fileEntry(path.Join("extensions", name), src, true, opts)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	mapped := make([]string, len(paths))
+	for i, p := range paths {
+		src, dest, glob := splitPathArg(p)
+		switch {
+		case dest != "":
+			mapped[i] = p
+		case glob:
+			mapped[i] = p + "=lib"
+		default:
+			mapped[i] = p + "=" + path.Join("lib", filepath.Base(src))
+		}
+	}
+	files, err := collectEntries(mapped, opts)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range files {
+		if src, ok := names[path.Base(e.name)]; ok && path.Dir(e.name) == "extensions" {
+			return nil, fmt.Errorf("%s is both the extension %s and a path of the layer", e.name, src)
+		}
+	}
+	return append(entries, files...), nil
+}
+
+// compressLayer writes a Lambda layer with the extensions and paths to the zip at outZipPath.
+func compressLayer(outZipPath string, extensions []string, paths []string, opts zipOptions) error {
+	entries, err := layerEntries(extensions, paths, opts)
+	if err != nil {
+		return err
+	}
+	return writeZipFile(outZipPath, entries)
+}
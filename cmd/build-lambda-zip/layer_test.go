@@ -0,0 +1,48 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLayerEntries(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]os.FileMode{
+		"telemetry":       0644,
+		"bin/logs":        0644,
+		"certs/ca.pem":    0644,
+		"libfoo.so":       0644,
+		"libbar.so":       0644,
+		"config/ext.yaml": 0644,
+	})
+	opts := zipOptions{reproducible: true, modTime: defaultModTime}
+
+	entries, err := layerEntries(
+		[]string{filepath.Join(root, "telemetry"), filepath.Join(root, "bin", "logs") + "=log-shipper"},
+		[]string{filepath.Join(root, "certs"), filepath.Join(root, "*.so"), filepath.Join(root, "config", "ext.yaml") + "=etc/ext.yaml"},
+		opts)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]os.FileMode{
+		"extensions/telemetry":   0755,
+		"extensions/log-shipper": 0755,
+		"lib/certs/ca.pem":       0644,
+		"lib/libfoo.so":          0644,
+		"lib/libbar.so":          0644,
+		"etc/ext.yaml":           0644,
+	}, entryModes(entries))
+
+	_, err = layerEntries([]string{filepath.Join(root, "telemetry"), filepath.Join(root, "bin", "logs") + "=telemetry"}, nil, opts)
+	assert.EqualError(t, err, "extensions "+filepath.Join(root, "telemetry")+" and "+filepath.Join(root, "bin", "logs")+" are both named telemetry")
+
+	_, err = layerEntries([]string{filepath.Join(root, "telemetry")}, []string{filepath.Join(root, "bin", "logs") + "=extensions/telemetry"}, opts)
+	assert.Error(t, err)
+
+	_, err = layerEntries([]string{filepath.Join(root, "telemetry") + "=sub/dir"}, nil, opts)
+	assert.Error(t, err)
+}
@@ -3,10 +3,8 @@
 package main
 
 import (
-	"archive/zip"
 	"flag"
 	"fmt"
-	"io/ioutil" //nolint: staticcheck
 	"log"
 	"os"
 	"path/filepath"
@@ -15,96 +13,201 @@ import (
 const usage = `build-lambda-zip - Puts an executable and supplemental files into a zip file that works with AWS Lambda.
 usage:
   build-lambda-zip [options] handler-exe [paths...]
+  build-lambda-zip --layer [options] [--extension exe...] [paths...]
+    builds a Lambda layer, which is extracted to /opt. Each extension is put in extensions/ as an
+    executable, optionally renamed with exe=name. Paths without =dest are put in lib/.
+  build-lambda-zip build [options] packages...
+    builds the Go packages for Lambda and zips them in one step, see build-lambda-zip build --help.
+    If a file named build exists in the current directory, it is zipped as the handler executable instead.
+paths:
+  Each path is a file, a directory or a glob pattern, optionally followed by =dest to choose
+  where it goes in the zip, such as config/prod.json=config.json or static=public.
+  A path that exists is taken as it is, even if its name contains = or glob characters.
+  Absolute paths are stored without their leading /. Paths starting with .. would be stored
+  outside of the zip root, so they must be given a =dest.
+  Directories are added recursively. Executable bits are kept, as are relative symlinks that
+  stay inside the directory, while other symlinks are replaced by the files they point to.
+  Files in directories and glob matches are skipped if their path in the zip matches a pattern
+  in the ignore file.
+Zips that exceed the Lambda limit of 250 MiB unzipped are rejected. A warning is printed for zips over
+50 MiB, which must be deployed from S3 rather than uploaded directly.
 options:
-  -o, --output  output file path for the zip. (default: ${handler-exe}.zip)
-  -h, --help    prints usage
+  -o, --output      output file path for the zip. (default: ${handler-exe}.zip, layer.zip with --layer,
+                    or ${handler-exe}.tar with --format oci)
+  --reproducible    produce a byte-identical zip for identical inputs, with fixed modification
+                    times and permission bits. The time is taken from SOURCE_DATE_EPOCH when set.
+                    Use --reproducible=false to keep the times and permissions of the files. (default: true)
+  --ignore-file     file of .gitignore style patterns to exclude. (default: .lambdaignore)
+  --layer           build a layer rather than a function.
+  --extension       an extension executable to add to the layer. May be repeated.
+  --report          print the size and compressed size of each file in the zip.
+  --manifest        output file path for a JSON manifest with the size and SHA-256 hash of each file
+                    and of the zip. The hash of the zip is base64 encoded, like the CodeSha256 of Lambda.
+  --format          zip, or oci for a container image as a tar of an OCI image layout, which tools such
+                    as skopeo and crane can push. The files are put in /var/task, with the handler
+                    executable as the ENTRYPOINT, like in the provided.al2023 base image. (default: zip)
+  --base            for --format oci, an OCI image layout directory or tar to use as the base image,
+                    such as public.ecr.aws/lambda/provided:al2023 copied with skopeo. (default: no base)
+  --arch            for --format oci, the architecture of the image, amd64 or arm64. (default: amd64)
+  -h, --help        prints usage
 `
 
 func main() {
+	if isBuildCommand(os.Args[1:]) {
+		if err := buildMain(os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	var outputZip string
+	var reproducible bool
+	var ignoreFile string
+	var layer bool
+	var report bool
+	var manifestPath string
+	var format string
+	var image imageOptions
+	var extensions stringsFlag
 	flag.StringVar(&outputZip, "o", "", "")
 	flag.StringVar(&outputZip, "output", "", "")
+	flag.BoolVar(&reproducible, "reproducible", true, "")
+	flag.StringVar(&ignoreFile, "ignore-file", ".lambdaignore", "")
+	flag.BoolVar(&layer, "layer", false, "")
+	flag.Var(&extensions, "extension", "")
+	flag.BoolVar(&report, "report", false, "")
+	flag.StringVar(&manifestPath, "manifest", "", "")
+	flag.StringVar(&format, "format", "zip", "")
+	flag.StringVar(&image.base, "base", "", "")
+	flag.StringVar(&image.arch, "arch", "amd64", "")
 	flag.Usage = func() {
 		fmt.Fprint(os.Stderr, usage)
 	}
 	flag.Parse()
-	if len(flag.Args()) == 0 {
+	if !layer && len(extensions) > 0 {
+		log.Fatal("--extension can only be used with --layer")
+	}
+	if len(flag.Args()) == 0 && len(extensions) == 0 {
 		log.Fatal("no input provided")
 	}
-	inputExe := flag.Arg(0)
+	switch format {
+	case "zip":
+	case "oci":
+		if layer || report || manifestPath != "" {
+			log.Fatal("--layer, --report and --manifest can only be used with --format zip")
+		}
+	default:
+		log.Fatalf("unsupported format %q, must be zip or oci", format)
+	}
 	if outputZip == "" {
-		outputZip = fmt.Sprintf("%s.zip", filepath.Base(inputExe))
+		switch {
+		case layer:
+			outputZip = "layer.zip"
+		case format == "oci":
+			outputZip = fmt.Sprintf("%s.tar", filepath.Base(flag.Arg(0)))
+		default:
+			outputZip = fmt.Sprintf("%s.zip", filepath.Base(flag.Arg(0)))
+		}
+	}
+	opts, err := newZipOptions(reproducible)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if opts.ignore, err = loadIgnoreFile(ignoreFile); err != nil {
+		log.Fatalf("failed to read ignore file: %v", err)
 	}
-	if err := compressExeAndArgs(outputZip, inputExe, flag.Args()[1:]); err != nil {
+	if format == "oci" {
+		image.modTime = opts.modTime
+		err = writeFunctionImage(outputZip, flag.Arg(0), flag.Args()[1:], opts, image)
+	} else if layer {
+		err = compressLayer(outputZip, extensions, flag.Args(), opts)
+	} else {
+		err = compressExeAndArgs(outputZip, flag.Arg(0), flag.Args()[1:], opts)
+	}
+	if err != nil {
 		log.Fatalf("failed to compress file: %v", err)
 	}
 	log.Printf("wrote %s", outputZip)
-}
 
-func writeExe(writer *zip.Writer, pathInZip string, data []byte) error {
-	if pathInZip != "bootstrap" {
-		header := &zip.FileHeader{Name: "bootstrap", Method: zip.Deflate}
-		header.SetMode(0755 | os.ModeSymlink)
-		link, err := writer.CreateHeader(header)
+	if report || manifestPath != "" {
+		m, err := newManifest(outputZip)
 		if err != nil {
-			return err
+			log.Fatalf("failed to read zip: %v", err)
 		}
-		if _, err := link.Write([]byte(pathInZip)); err != nil {
-			return err
+		if report {
+			if err := printReport(os.Stdout, m); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if manifestPath != "" {
+			if err := writeManifest(manifestPath, m); err != nil {
+				log.Fatalf("failed to write manifest: %v", err)
+			}
+			log.Printf("wrote %s", manifestPath)
 		}
 	}
+}
 
-	exe, err := writer.CreateHeader(&zip.FileHeader{
-		CreatorVersion: 3 << 8,     // indicates Unix
-		ExternalAttrs:  0777 << 16, // -rwxrwxrwx file permissions
-		Name:           pathInZip,
-		Method:         zip.Deflate,
-	})
+// exeEntries returns the entries for the handler executable, plus a bootstrap symlink to it if it has another name.
+func exeEntries(pathInZip string, exePath string, opts zipOptions) ([]entry, error) {
+	exe, err := fileEntry(pathInZip, exePath, true, opts)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if pathInZip == "bootstrap" {
+		return []entry{exe}, nil
 	}
+	return []entry{symlinkEntry("bootstrap", pathInZip, opts), exe}, nil
+}
 
-	_, err = exe.Write(data)
-	return err
+// functionEntries returns the entries of a function with the handler executable and the paths.
+func functionEntries(exePath string, args []string, opts zipOptions) ([]entry, error) {
+	entries, err := exeEntries(filepath.Base(exePath), exePath, opts)
+	if err != nil {
+		return nil, err
+	}
+	files, err := collectEntries(args, opts)
+	if err != nil {
+		return nil, err
+	}
+	return append(entries, files...), nil
 }
 
-func compressExeAndArgs(outZipPath string, exePath string, args []string) error {
-	zipFile, err := os.Create(outZipPath)
+// writeFunctionImage writes an OCI image of the function with the handler executable and the paths to outPath.
+func writeFunctionImage(outPath string, exePath string, args []string, opts zipOptions, image imageOptions) error {
+	entries, err := functionEntries(exePath, args, opts)
 	if err != nil {
 		return err
 	}
-	defer func() {
-		closeErr := zipFile.Close()
-		if closeErr != nil {
-			fmt.Fprintf(os.Stderr, "Failed to close zip file: %v\n", closeErr)
-		}
-	}()
+	return writeImage(outPath, entries, image)
+}
 
-	zipWriter := zip.NewWriter(zipFile)
-	defer zipWriter.Close()
-	data, err := ioutil.ReadFile(exePath)
+func compressExeAndArgs(outZipPath string, exePath string, args []string, opts zipOptions) error {
+	entries, err := functionEntries(exePath, args, opts)
 	if err != nil {
 		return err
 	}
+	return writeZipFile(outZipPath, entries)
+}
 
-	err = writeExe(zipWriter, filepath.Base(exePath), data)
+// writeZipFile writes the entries to a new zip file at outZipPath.
+// The file is removed if it exceeds the unzipped size limit of Lambda.
+func writeZipFile(outZipPath string, entries []entry) error {
+	zipFile, err := os.Create(outZipPath)
 	if err != nil {
 		return err
 	}
-
-	for _, arg := range args {
-		writer, err := zipWriter.Create(arg)
-		if err != nil {
-			return err
-		}
-		data, err := ioutil.ReadFile(arg)
-		if err != nil {
-			return err
-		}
-		_, err = writer.Write(data)
-		if err != nil {
-			return err
-		}
+	err = writeEntries(zipFile, entries)
+	// the file is closed before the size is checked, and before it is removed
+	if closeErr := zipFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := checkSizeLimits(outZipPath); err != nil {
+		os.Remove(outZipPath)
+		return err
 	}
-	return err
+	return nil
 }
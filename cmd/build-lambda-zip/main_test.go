@@ -4,13 +4,16 @@ package main
 
 import (
 	"archive/zip"
+	"bytes"
 	"fmt"
 	"io/ioutil" //nolint: staticcheck
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -52,7 +55,7 @@ func TestSizes(t *testing.T) {
 			gocmd.Stderr = os.Stderr
 			require.NoError(t, gocmd.Run())
 			require.NoError(t, os.Chdir(filepath.Dir(binPath)))
-			require.NoError(t, compressExeAndArgs(zipPath, binPath, []string{}))
+			require.NoError(t, compressExeAndArgs(zipPath, binPath, []string{}, zipOptions{reproducible: true, modTime: defaultModTime}))
 
 			binInfo, err := os.Stat(binPath)
 			require.NoError(t, err)
@@ -82,7 +85,7 @@ func TestCompressExeAndArgs(t *testing.T) {
 	}
 	outZipPath := filepath.Join(tempDir, "lambda.zip")
 
-	err = compressExeAndArgs(outZipPath, filePaths[0], filePaths[1:])
+	err = compressExeAndArgs(outZipPath, filePaths[0], filePaths[1:], zipOptions{reproducible: true, modTime: defaultModTime})
 	require.NoError(t, err)
 
 	t.Run("handler exe configured in zip root", func(t *testing.T) {
@@ -129,7 +132,7 @@ func TestCompressExeAndArgs(t *testing.T) {
 	eachFile:
 		for _, path := range filePaths[1:] {
 			for _, zipFileEntry := range zipReader.File {
-				if zipFileEntry.Name == path {
+				if zipFileEntry.Name == strings.TrimPrefix(path, "/") {
 					continue eachFile
 				}
 			}
@@ -142,7 +145,11 @@ func TestCompressExeAndArgs(t *testing.T) {
 		zipReader, err := zip.OpenReader(outZipPath)
 		require.NoError(t, err)
 		defer zipReader.Close()
-		expectedIndex := 0
+		expectedIndex := map[string]int{filepath.Base(filePaths[0]): 0}
+		for i, path := range filePaths[1:] {
+			expectedIndex[strings.TrimPrefix(path, "/")] = i + 1
+		}
+		regularFiles := 0
 		for _, zf := range zipReader.File {
 			if zf.FileInfo().Mode().IsRegular() {
 				f, err := zf.Open()
@@ -150,10 +157,62 @@ func TestCompressExeAndArgs(t *testing.T) {
 				defer f.Close()
 				content, err := ioutil.ReadAll(f)
 				require.NoError(t, err)
-				assert.Equal(t, fmt.Sprintf("Hello file %d!", expectedIndex), string(content), "in file: %s", zf.Name)
-				expectedIndex++
+				require.Contains(t, expectedIndex, zf.Name)
+				assert.Equal(t, fmt.Sprintf("Hello file %d!", expectedIndex[zf.Name]), string(content), "in file: %s", zf.Name)
+				regularFiles++
 			}
 		}
+		assert.Equal(t, len(filePaths), regularFiles)
 	})
 
 }
+
+func TestReproducibleZip(t *testing.T) {
+	tempDir, err := ioutil.TempDir("/tmp", "build-lambda-zip")
+	require.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+
+	exePath := filepath.Join(tempDir, "handler")
+	configPath := filepath.Join(tempDir, "config.json")
+	require.NoError(t, ioutil.WriteFile(exePath, []byte("not really an exe"), 0700))
+	require.NoError(t, ioutil.WriteFile(configPath, []byte("{}"), 0600))
+
+	build := func(name string) []byte {
+		zipPath := filepath.Join(tempDir, name)
+		opts, err := newZipOptions(true)
+		require.NoError(t, err)
+		require.NoError(t, compressExeAndArgs(zipPath, exePath, []string{configPath}, opts))
+		data, err := ioutil.ReadFile(zipPath)
+		require.NoError(t, err)
+		return data
+	}
+
+	first := build("first.zip")
+	later := time.Now().Add(time.Hour)
+	require.NoError(t, os.Chtimes(exePath, later, later))
+	require.NoError(t, os.Chmod(configPath, 0664))
+	assert.Equal(t, first, build("second.zip"), "zips of identical inputs should be byte-identical")
+
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	withEpoch := build("epoch.zip")
+	assert.NotEqual(t, first, withEpoch)
+
+	zipReader, err := zip.NewReader(bytes.NewReader(withEpoch), int64(len(withEpoch)))
+	require.NoError(t, err)
+	names := make([]string, len(zipReader.File))
+	for i, zf := range zipReader.File {
+		names[i] = zf.Name
+		assert.True(t, zf.Modified.Equal(time.Unix(1700000000, 0)), "modified time of %s: %v", zf.Name, zf.Modified)
+		switch zf.Name {
+		case "handler":
+			assert.Equal(t, os.FileMode(0755), zf.Mode())
+		case strings.TrimPrefix(configPath, "/"):
+			assert.Equal(t, os.FileMode(0644), zf.Mode())
+		}
+	}
+	assert.Equal(t, []string{"bootstrap", "handler", strings.TrimPrefix(configPath, "/")}, names)
+
+	t.Setenv("SOURCE_DATE_EPOCH", "yesterday")
+	_, err = newZipOptions(true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil" //nolint: staticcheck
+	"log"
+	"os"
+	"sort"
+	"text/tabwriter"
+)
+
+// The deployment package limits of Lambda.
+// See https://docs.aws.amazon.com/lambda/latest/dg/gettingstarted-limits.html
+const (
+	maxZippedSize   = 50 << 20
+	maxUnzippedSize = 250 << 20
+)
+
+// manifest describes a zip file and the files in it.
+type manifest struct {
+	Zip  string `json:"zip"`
+	Size int64  `json:"size"`
+	// CodeSha256 is the base64 encoded SHA-256 hash of the zip, as reported by Lambda for the function code.
+	CodeSha256       string         `json:"codeSha256"`
+	UncompressedSize uint64         `json:"uncompressedSize"`
+	Files            []manifestFile `json:"files"`
+}
+
+type manifestFile struct {
+	Name           string `json:"name"`
+	Mode           string `json:"mode"`
+	Size           uint64 `json:"size"`
+	CompressedSize uint64 `json:"compressedSize"`
+	// Sha256 is the hex encoded SHA-256 hash of the contents of the file, or of the target of a symlink.
+	Sha256 string `json:"sha256"`
+}
+
+// checkSizeLimits returns an error if the zip at zipPath is too large to deploy to Lambda.
+// A zip that is only too large to upload directly is allowed with a warning, since it can be deployed from S3.
+func checkSizeLimits(zipPath string) error {
+	zipReader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return err
+	}
+	defer zipReader.Close()
+	info, err := os.Stat(zipPath)
+	if err != nil {
+		return err
+	}
+	var unzipped uint64
+	for _, zf := range zipReader.File {
+		unzipped += zf.UncompressedSize64
+	}
+	if info.Size() > maxZippedSize {
+		log.Printf("warning: %s is %s, which exceeds the Lambda limit of %s for a zipped deployment package uploaded directly, deploy it from S3",
+			zipPath, formatSize(uint64(info.Size())), formatSize(maxZippedSize))
+	}
+	if unzipped > maxUnzippedSize {
+		return fmt.Errorf("%s is %s unzipped, which exceeds the Lambda limit of %s for an unzipped deployment package",
+			zipPath, formatSize(unzipped), formatSize(maxUnzippedSize))
+	}
+	return nil
+}
+
+// newManifest reads the zip at zipPath and hashes it and each of the files in it.
+func newManifest(zipPath string) (*manifest, error) {
+	data, err := ioutil.ReadFile(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	m := &manifest{
+		Zip:        zipPath,
+		Size:       int64(len(data)),
+		CodeSha256: base64.StdEncoding.EncodeToString(sum[:]),
+		Files:      []manifestFile{},
+	}
+
+	zipReader, err := zip.OpenReader(zipPath)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+	for _, zf := range zipReader.File {
+		f, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", zf.Name, err)
+		}
+		m.UncompressedSize += zf.UncompressedSize64
+		m.Files = append(m.Files, manifestFile{
+			Name:           zf.Name,
+			Mode:           zf.Mode().String(),
+			Size:           zf.UncompressedSize64,
+			CompressedSize: zf.CompressedSize64,
+			Sha256:         hex.EncodeToString(hash.Sum(nil)),
+		})
+	}
+	return m, nil
+}
+
+// writeManifest writes the manifest as JSON to the file at path.
+func writeManifest(path string, m *manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// printReport writes a table of the sizes of the files in the zip to w, largest first.
+func printReport(w io.Writer, m *manifest) error {
+	files := make([]manifestFile, len(m.Files))
+	copy(files, m.Files)
+	sort.SliceStable(files, func(i, j int) bool { return files[i].CompressedSize > files[j].CompressedSize })
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "SIZE\tCOMPRESSED\t\t\n")
+	for _, f := range files {
+		fmt.Fprintf(tw, "%s\t%s\t\t%s\n", formatSize(f.Size), formatSize(f.CompressedSize), f.Name)
+	}
+	fmt.Fprintf(tw, "%s\t%s\t\t%s\n", formatSize(m.UncompressedSize), formatSize(uint64(m.Size)), m.Zip)
+	return tw.Flush()
+}
+
+// formatSize formats a number of bytes with a binary unit, such as 1.5 MiB.
+func formatSize(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
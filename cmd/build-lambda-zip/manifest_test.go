@@ -0,0 +1,113 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]os.FileMode{"bootstrap": 0755, "config.json": 0644})
+	zipPath := filepath.Join(root, "function.zip")
+	opts := zipOptions{reproducible: true, modTime: defaultModTime}
+	require.NoError(t, compressExeAndArgs(zipPath, filepath.Join(root, "bootstrap"), []string{filepath.Join(root, "config.json") + "=config.json"}, opts))
+
+	m, err := newManifest(zipPath)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(zipPath)
+	require.NoError(t, err)
+	zipSum := sha256.Sum256(data)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(zipSum[:]), m.CodeSha256)
+	assert.Equal(t, int64(len(data)), m.Size)
+
+	require.Len(t, m.Files, 2)
+	configSum := sha256.Sum256([]byte("config.json"))
+	assert.Equal(t, manifestFile{
+		Name:           "config.json",
+		Mode:           "-rw-r--r--",
+		Size:           uint64(len("config.json")),
+		CompressedSize: m.Files[1].CompressedSize,
+		Sha256:         hex.EncodeToString(configSum[:]),
+	}, m.Files[1])
+	assert.Equal(t, "bootstrap", m.Files[0].Name)
+	assert.Equal(t, uint64(len("bootstrap")+len("config.json")), m.UncompressedSize)
+
+	manifestPath := filepath.Join(root, "manifest.json")
+	require.NoError(t, writeManifest(manifestPath, m))
+	data, err = os.ReadFile(manifestPath)
+	require.NoError(t, err)
+	var written manifest
+	require.NoError(t, json.Unmarshal(data, &written))
+	assert.Equal(t, *m, written)
+
+	var report bytes.Buffer
+	require.NoError(t, printReport(&report, m))
+	lines := strings.Split(strings.TrimSpace(report.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Contains(t, lines[0], "COMPRESSED")
+	assert.True(t, strings.HasSuffix(lines[3], zipPath))
+}
+
+func TestFormatSize(t *testing.T) {
+	assert.Equal(t, "512 B", formatSize(512))
+	assert.Equal(t, "1.5 KiB", formatSize(1536))
+	assert.Equal(t, "50.0 MiB", formatSize(maxZippedSize))
+	assert.Equal(t, "1.0 GiB", formatSize(1<<30))
+}
+
+func TestCheckSizeLimits(t *testing.T) {
+	writeZip := func(name string, method uint16, r io.Reader) string {
+		zipPath := filepath.Join(t.TempDir(), name)
+		f, err := os.Create(zipPath)
+		require.NoError(t, err)
+		defer f.Close()
+		zipWriter := zip.NewWriter(f)
+		w, err := zipWriter.CreateHeader(&zip.FileHeader{Name: "data", Method: method})
+		require.NoError(t, err)
+		_, err = io.Copy(w, r)
+		require.NoError(t, err)
+		require.NoError(t, zipWriter.Close())
+		return zipPath
+	}
+
+	small := writeZip("small.zip", zip.Deflate, strings.NewReader("hello"))
+	assert.NoError(t, checkSizeLimits(small))
+
+	zipped := writeZip("zipped.zip", zip.Store, io.LimitReader(rand.New(rand.NewSource(1)), maxZippedSize+1))
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	assert.NoError(t, checkSizeLimits(zipped))
+	assert.Contains(t, logs.String(), "exceeds the Lambda limit of 50.0 MiB for a zipped deployment package uploaded directly")
+
+	unzipped := writeZip("unzipped.zip", zip.Deflate, io.LimitReader(zeros{}, maxUnzippedSize+1))
+	err := checkSizeLimits(unzipped)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds the Lambda limit of 250.0 MiB for an unzipped deployment package")
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
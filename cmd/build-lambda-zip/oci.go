@@ -0,0 +1,447 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil" //nolint: staticcheck
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The media types of an OCI image, and of the Docker images that registries also serve.
+// See https://github.com/opencontainers/image-spec
+const (
+	mediaTypeIndex          = "application/vnd.oci.image.index.v1+json"
+	mediaTypeManifest       = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeConfig         = "application/vnd.oci.image.config.v1+json"
+	mediaTypeLayer          = "application/vnd.oci.image.layer.v1.tar+gzip"
+	mediaTypeDockerList     = "application/vnd.docker.distribution.manifest.list.v2+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	mediaTypeDockerConfig   = "application/vnd.docker.container.image.v1+json"
+	mediaTypeDockerLayer    = "application/vnd.docker.image.rootfs.diff.tar.gzip"
+)
+
+// taskRoot is where Lambda expects the function code in a container image, as in the provided.al2023 base image.
+const taskRoot = "var/task"
+
+// imageOptions configures an OCI image.
+type imageOptions struct {
+	// base is the path of an OCI image layout, as a directory or a tar file, to add the function to.
+	// When empty, the function is the only layer of the image, which works because the executable
+	// implements the Lambda runtime API itself.
+	base    string
+	arch    string
+	modTime time.Time
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *platform         `json:"platform,omitempty"`
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+type imageIndex struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Manifests     []descriptor `json:"manifests"`
+}
+
+type imageManifest struct {
+	SchemaVersion int          `json:"schemaVersion"`
+	MediaType     string       `json:"mediaType,omitempty"`
+	Config        descriptor   `json:"config"`
+	Layers        []descriptor `json:"layers"`
+}
+
+func digestOf(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func blobPath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+// image is an OCI image being built, with the blobs it references by digest.
+type image struct {
+	manifest imageManifest
+	config   map[string]interface{}
+	blobs    map[string][]byte
+}
+
+// emptyImage returns an image with no layers.
+func emptyImage(arch string) *image {
+	return &image{
+		manifest: imageManifest{SchemaVersion: 2, MediaType: mediaTypeManifest},
+		config: map[string]interface{}{
+			"architecture": arch,
+			"os":           "linux",
+			"config":       map[string]interface{}{},
+			"rootfs":       map[string]interface{}{"type": "layers", "diff_ids": []interface{}{}},
+		},
+		blobs: map[string][]byte{},
+	}
+}
+
+// readLayout returns the files of the OCI image layout at layoutPath, which is a directory or a tar file.
+func readLayout(layoutPath string) (map[string][]byte, error) {
+	info, err := os.Stat(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte)
+	if info.IsDir() {
+		err = filepath.Walk(layoutPath, func(p string, info os.FileInfo, err error) error {
+			if err != nil || !info.Mode().IsRegular() {
+				return err
+			}
+			rel, err := filepath.Rel(layoutPath, p)
+			if err != nil {
+				return err
+			}
+			files[filepath.ToSlash(rel)], err = ioutil.ReadFile(p)
+			return err
+		})
+		return files, err
+	}
+
+	f, err := os.Open(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if files[path.Clean(header.Name)], err = ioutil.ReadAll(tr); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// baseImage reads the image for the architecture from the OCI image layout at layoutPath.
+func baseImage(layoutPath, arch string) (*image, error) {
+	files, err := readLayout(layoutPath)
+	if err != nil {
+		return nil, err
+	}
+	blob := func(d descriptor) ([]byte, error) {
+		data, ok := files[blobPath(d.Digest)]
+		if !ok {
+			return nil, fmt.Errorf("blob %s is missing from %s", d.Digest, layoutPath)
+		}
+		if digestOf(data) != d.Digest {
+			return nil, fmt.Errorf("blob %s in %s does not match its digest", d.Digest, layoutPath)
+		}
+		return data, nil
+	}
+
+	indexJSON, ok := files["index.json"]
+	if !ok {
+		return nil, fmt.Errorf("%s is not an OCI image layout, index.json is missing", layoutPath)
+	}
+	var index imageIndex
+	if err := json.Unmarshal(indexJSON, &index); err != nil {
+		return nil, fmt.Errorf("index.json: %v", err)
+	}
+	var manifestJSON []byte
+	for manifestJSON == nil {
+		var selected *descriptor
+		for i, d := range index.Manifests {
+			if d.Platform == nil || (d.Platform.OS == "linux" && d.Platform.Architecture == arch) {
+				selected = &index.Manifests[i]
+				break
+			}
+		}
+		if selected == nil {
+			return nil, fmt.Errorf("%s has no image for linux/%s", layoutPath, arch)
+		}
+		data, err := blob(*selected)
+		if err != nil {
+			return nil, err
+		}
+		if selected.MediaType != mediaTypeIndex && selected.MediaType != mediaTypeDockerList {
+			manifestJSON = data
+		} else if err := json.Unmarshal(data, &index); err != nil {
+			return nil, fmt.Errorf("index %s: %v", selected.Digest, err)
+		}
+	}
+
+	img := &image{blobs: make(map[string][]byte)}
+	if err := json.Unmarshal(manifestJSON, &img.manifest); err != nil {
+		return nil, fmt.Errorf("manifest: %v", err)
+	}
+	configJSON, err := blob(img.manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(configJSON, &img.config); err != nil {
+		return nil, fmt.Errorf("config: %v", err)
+	}
+	if baseArch, _ := img.config["architecture"].(string); baseArch != arch {
+		return nil, fmt.Errorf("%s is for %s, not %s", layoutPath, baseArch, arch)
+	}
+	for _, layer := range img.manifest.Layers {
+		if img.blobs[layer.Digest], err = blob(layer); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+// layerTar returns the entries as a gzipped tar of the files under taskRoot, and the digest of the uncompressed tar.
+func layerTar(entries []entry, modTime time.Time) (layer []byte, diffID string, err error) {
+	sorted := make([]entry, len(entries))
+	for i, e := range entries {
+		e.name = path.Join(taskRoot, e.name)
+		sorted[i] = e
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	var tarBuf bytes.Buffer
+	tw := tar.NewWriter(&tarBuf)
+	dirs := make(map[string]bool)
+	var addDirs func(dir string) error
+	addDirs = func(dir string) error {
+		if dir == "." || dir == "/" || dirs[dir] {
+			return nil
+		}
+		if err := addDirs(path.Dir(dir)); err != nil {
+			return err
+		}
+		dirs[dir] = true
+		return tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir + "/", Mode: 0755, ModTime: modTime})
+	}
+	for i, e := range sorted {
+		if i > 0 && e.name == sorted[i-1].name {
+			return nil, "", fmt.Errorf("duplicate path in image: %s", e.name)
+		}
+		if err := addDirs(path.Dir(e.name)); err != nil {
+			return nil, "", err
+		}
+		header := &tar.Header{Name: e.name, Mode: int64(e.mode.Perm()), ModTime: e.modTime}
+		if e.mode&os.ModeSymlink != 0 {
+			header.Typeflag = tar.TypeSymlink
+			header.Linkname = e.link
+			if err := tw.WriteHeader(header); err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+		if err := writeTarFile(tw, header, e.path); err != nil {
+			return nil, "", fmt.Errorf("%s: %v", e.name, err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, "", err
+	}
+
+	var gzBuf bytes.Buffer
+	gz := gzip.NewWriter(&gzBuf)
+	if _, err := gz.Write(tarBuf.Bytes()); err != nil {
+		return nil, "", err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, "", err
+	}
+	return gzBuf.Bytes(), digestOf(tarBuf.Bytes()), nil
+}
+
+func writeTarFile(tw *tar.Writer, header *tar.Header, filePath string) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header.Typeflag = tar.TypeReg
+	header.Size = info.Size()
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, f, header.Size)
+	return err
+}
+
+// addFunction adds the entries to the image as a layer, and runs the handler executable on start.
+func (img *image) addFunction(entries []entry, modTime time.Time) error {
+	// each history entry that is not an empty_layer describes a layer, in order, so they must line up
+	history, _ := img.config["history"].([]interface{})
+	layers := 0
+	for _, h := range history {
+		if h, _ := h.(map[string]interface{}); h["empty_layer"] != true {
+			layers++
+		}
+	}
+	if len(history) > 0 && layers != len(img.manifest.Layers) {
+		return fmt.Errorf("the history of the base image describes %d layers, but it has %d", layers, len(img.manifest.Layers))
+	}
+
+	layer, diffID, err := layerTar(entries, modTime)
+	if err != nil {
+		return err
+	}
+	layerDesc := descriptor{MediaType: mediaTypeLayer, Digest: digestOf(layer), Size: int64(len(layer))}
+	if img.manifest.MediaType == mediaTypeDockerManifest {
+		layerDesc.MediaType = mediaTypeDockerLayer
+	}
+	img.blobs[layerDesc.Digest] = layer
+	img.manifest.Layers = append(img.manifest.Layers, layerDesc)
+
+	created := modTime.UTC().Format(time.RFC3339)
+	img.config["created"] = created
+	rootfs, _ := img.config["rootfs"].(map[string]interface{})
+	if rootfs == nil {
+		rootfs = map[string]interface{}{"type": "layers"}
+		img.config["rootfs"] = rootfs
+	}
+	diffIDs, _ := rootfs["diff_ids"].([]interface{})
+	rootfs["diff_ids"] = append(diffIDs, diffID)
+	// a base image without history keeps none, rather than one that only describes the last layer
+	if len(history) > 0 || len(img.manifest.Layers) == 1 {
+		img.config["history"] = append(history, map[string]interface{}{"created": created, "created_by": "build-lambda-zip"})
+	}
+
+	config, _ := img.config["config"].(map[string]interface{})
+	if config == nil {
+		config = map[string]interface{}{}
+		img.config["config"] = config
+	}
+	// the Cmd of the base image would be passed as arguments to the handler executable
+	config["Entrypoint"] = []string{"/" + taskRoot + "/bootstrap"}
+	delete(config, "Cmd")
+	config["WorkingDir"] = "/" + taskRoot
+	env, _ := config["Env"].([]interface{})
+	hasTaskRoot := false
+	for _, v := range env {
+		if s, _ := v.(string); strings.HasPrefix(s, "LAMBDA_TASK_ROOT=") {
+			hasTaskRoot = true
+		}
+	}
+	if !hasTaskRoot {
+		config["Env"] = append(env, "LAMBDA_TASK_ROOT=/"+taskRoot)
+	}
+	return nil
+}
+
+// write writes the image to w as a tar of an OCI image layout, with the image tagged latest.
+func (img *image) write(w io.Writer, modTime time.Time) error {
+	configJSON, err := json.Marshal(img.config)
+	if err != nil {
+		return err
+	}
+	img.manifest.Config = descriptor{MediaType: mediaTypeConfig, Digest: digestOf(configJSON), Size: int64(len(configJSON))}
+	if img.manifest.MediaType == mediaTypeDockerManifest {
+		img.manifest.Config.MediaType = mediaTypeDockerConfig
+	}
+	img.blobs[img.manifest.Config.Digest] = configJSON
+
+	manifestJSON, err := json.Marshal(img.manifest)
+	if err != nil {
+		return err
+	}
+	arch, _ := img.config["architecture"].(string)
+	manifestDesc := descriptor{
+		MediaType:   mediaTypeManifest,
+		Digest:      digestOf(manifestJSON),
+		Size:        int64(len(manifestJSON)),
+		Annotations: map[string]string{"org.opencontainers.image.ref.name": "latest"},
+		Platform:    &platform{Architecture: arch, OS: "linux"},
+	}
+	if img.manifest.MediaType == mediaTypeDockerManifest {
+		manifestDesc.MediaType = mediaTypeDockerManifest
+	}
+	img.blobs[manifestDesc.Digest] = manifestJSON
+	indexJSON, err := json.Marshal(imageIndex{SchemaVersion: 2, MediaType: mediaTypeIndex, Manifests: []descriptor{manifestDesc}})
+	if err != nil {
+		return err
+	}
+
+	digests := make([]string, 0, len(img.blobs))
+	for digest := range img.blobs {
+		digests = append(digests, digest)
+	}
+	sort.Strings(digests)
+
+	tw := tar.NewWriter(w)
+	writeFile := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0644, Size: int64(len(data)), ModTime: modTime}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := writeFile("oci-layout", []byte(`{"imageLayoutVersion":"1.0.0"}`)); err != nil {
+		return err
+	}
+	if err := writeFile("index.json", indexJSON); err != nil {
+		return err
+	}
+	for _, dir := range []string{"blobs/", "blobs/sha256/"} {
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: dir, Mode: 0755, ModTime: modTime}); err != nil {
+			return err
+		}
+	}
+	for _, digest := range digests {
+		if err := writeFile(blobPath(digest), img.blobs[digest]); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+// writeImage writes an OCI image layout tar with the entries added to the base image to outPath.
+func writeImage(outPath string, entries []entry, opts imageOptions) error {
+	img := emptyImage(opts.arch)
+	if opts.base != "" {
+		var err error
+		if img, err = baseImage(opts.base, opts.arch); err != nil {
+			return fmt.Errorf("failed to read base image: %v", err)
+		}
+	}
+	if err := img.addFunction(entries, opts.modTime); err != nil {
+		return err
+	}
+
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	if err := img.write(out, opts.modTime); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,142 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// readImage returns the manifest, config and layer files of the single image in the OCI image layout tar at p.
+func readImage(t *testing.T, p string) (imageManifest, map[string]interface{}, []map[string]*tar.Header) {
+	files, err := readLayout(p)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"imageLayoutVersion":"1.0.0"}`, string(files["oci-layout"]))
+
+	var index imageIndex
+	require.NoError(t, json.Unmarshal(files["index.json"], &index))
+	require.Len(t, index.Manifests, 1)
+	assert.Equal(t, "latest", index.Manifests[0].Annotations["org.opencontainers.image.ref.name"])
+
+	var manifest imageManifest
+	require.NoError(t, json.Unmarshal(files[blobPath(index.Manifests[0].Digest)], &manifest))
+	var config map[string]interface{}
+	require.NoError(t, json.Unmarshal(files[blobPath(manifest.Config.Digest)], &config))
+
+	var layers []map[string]*tar.Header
+	for _, layer := range manifest.Layers {
+		data := files[blobPath(layer.Digest)]
+		require.Equal(t, layer.Digest, digestOf(data))
+		gz, err := gzip.NewReader(bytes.NewReader(data))
+		require.NoError(t, err)
+		tr := tar.NewReader(gz)
+		headers := map[string]*tar.Header{}
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			headers[header.Name] = header
+		}
+		layers = append(layers, headers)
+	}
+	return manifest, config, layers
+}
+
+func TestWriteFunctionImage(t *testing.T) {
+	root := t.TempDir()
+	writeTree(t, root, map[string]os.FileMode{"handler": 0755, "config.json": 0644})
+	opts := zipOptions{reproducible: true, modTime: defaultModTime}
+	image := imageOptions{arch: "arm64", modTime: defaultModTime}
+
+	basePath := filepath.Join(root, "base.tar")
+	require.NoError(t, writeFunctionImage(basePath, filepath.Join(root, "handler"), []string{filepath.Join(root, "config.json") + "=config.json"}, opts, image))
+
+	again := filepath.Join(root, "again.tar")
+	require.NoError(t, writeFunctionImage(again, filepath.Join(root, "handler"), []string{filepath.Join(root, "config.json") + "=config.json"}, opts, image))
+	first, err := os.ReadFile(basePath)
+	require.NoError(t, err)
+	second, err := os.ReadFile(again)
+	require.NoError(t, err)
+	assert.Equal(t, first, second, "images of identical inputs should be byte-identical")
+
+	manifest, config, layers := readImage(t, basePath)
+	assert.Equal(t, mediaTypeManifest, manifest.MediaType)
+	assert.Equal(t, "arm64", config["architecture"])
+	assert.Equal(t, map[string]interface{}{
+		"Entrypoint": []interface{}{"/var/task/bootstrap"},
+		"WorkingDir": "/var/task",
+		"Env":        []interface{}{"LAMBDA_TASK_ROOT=/var/task"},
+	}, config["config"])
+	require.Len(t, layers, 1)
+	assert.Equal(t, byte(tar.TypeDir), layers[0]["var/"].Typeflag)
+	assert.Equal(t, byte(tar.TypeSymlink), layers[0]["var/task/bootstrap"].Typeflag)
+	assert.Equal(t, "handler", layers[0]["var/task/bootstrap"].Linkname)
+	assert.Equal(t, int64(0755), layers[0]["var/task/handler"].Mode)
+	assert.Equal(t, int64(0644), layers[0]["var/task/config.json"].Mode)
+
+	t.Run("with a base image", func(t *testing.T) {
+		layoutDir := filepath.Join(root, "layout")
+		files, err := readLayout(basePath)
+		require.NoError(t, err)
+		for name, data := range files {
+			p := filepath.Join(layoutDir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+			require.NoError(t, os.WriteFile(p, data, 0644))
+		}
+
+		outPath := filepath.Join(root, "function.tar")
+		image := image
+		image.base = layoutDir
+		require.NoError(t, writeFunctionImage(outPath, filepath.Join(root, "handler"), nil, opts, image))
+
+		manifest, config, layers := readImage(t, outPath)
+		require.Len(t, manifest.Layers, 2)
+		require.Len(t, layers, 2)
+		assert.Contains(t, layers[0], "var/task/config.json")
+		assert.NotContains(t, layers[1], "var/task/config.json")
+		assert.Len(t, config["rootfs"].(map[string]interface{})["diff_ids"], 2)
+		assert.Len(t, config["history"], 2)
+		assert.Equal(t, []interface{}{"LAMBDA_TASK_ROOT=/var/task"}, config["config"].(map[string]interface{})["Env"])
+
+		image.arch = "amd64"
+		err = writeFunctionImage(outPath, filepath.Join(root, "handler"), nil, opts, image)
+		assert.Error(t, err)
+	})
+}
+
+func TestAddFunctionChecksHistory(t *testing.T) {
+	entries := []entry{symlinkEntry("bootstrap", "handler", zipOptions{reproducible: true, modTime: defaultModTime})}
+	base := func(history ...interface{}) *image {
+		img := emptyImage("amd64")
+		img.manifest.Layers = []descriptor{{MediaType: mediaTypeLayer, Digest: "sha256:base"}}
+		img.config["rootfs"] = map[string]interface{}{"type": "layers", "diff_ids": []interface{}{"sha256:base"}}
+		img.config["config"] = map[string]interface{}{"Cmd": []interface{}{"/bin/sh"}}
+		if history != nil {
+			img.config["history"] = history
+		}
+		return img
+	}
+
+	img := base(map[string]interface{}{"created_by": "ENV A=b", "empty_layer": true}, map[string]interface{}{"created_by": "ADD rootfs"})
+	require.NoError(t, img.addFunction(entries, defaultModTime))
+	assert.Len(t, img.config["history"], 3)
+	assert.NotContains(t, img.config["config"], "Cmd")
+
+	img = base()
+	require.NoError(t, img.addFunction(entries, defaultModTime))
+	assert.NotContains(t, img.config, "history")
+
+	img = base(map[string]interface{}{"created_by": "ENV A=b", "empty_layer": true})
+	assert.EqualError(t, img.addFunction(entries, defaultModTime), "the history of the base image describes 0 layers, but it has 1")
+}
@@ -0,0 +1,128 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package main
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// defaultModTime is the modification time of every file in a reproducible archive,
+// unless overridden by SOURCE_DATE_EPOCH. It is the earliest time a zip file can represent.
+var defaultModTime = time.Date(1980, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// zipOptions controls how entries are collected and written to the archive.
+type zipOptions struct {
+	// reproducible makes identical inputs produce byte-identical archives, by using modTime for
+	// every entry and normalizing the permission bits, instead of using those of the files on disk.
+	reproducible bool
+	modTime      time.Time
+	// ignore excludes files found in directories and by glob patterns.
+	ignore ignoreRules
+}
+
+// newZipOptions returns the options for a reproducible or non-reproducible archive,
+// honoring SOURCE_DATE_EPOCH for the modification time of a reproducible one.
+func newZipOptions(reproducible bool) (zipOptions, error) {
+	opts := zipOptions{reproducible: reproducible, modTime: defaultModTime}
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		seconds, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return opts, fmt.Errorf("invalid SOURCE_DATE_EPOCH %q: %v", epoch, err)
+		}
+		opts.modTime = time.Unix(seconds, 0).UTC()
+		if opts.modTime.Before(defaultModTime) {
+			opts.modTime = defaultModTime
+		}
+	}
+	return opts, nil
+}
+
+// entry is a file or symlink to be written to the archive.
+type entry struct {
+	name    string      // slash separated path in the archive
+	path    string      // path of the file on disk, empty for symlinks
+	link    string      // target of a symlink
+	mode    os.FileMode // permission bits, plus os.ModeSymlink for symlinks
+	modTime time.Time
+}
+
+// fileEntry returns the entry for the file at path, stored in the archive as name.
+func fileEntry(name, path string, executable bool, opts zipOptions) (entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return entry{}, err
+	}
+	if !info.Mode().IsRegular() {
+		return entry{}, fmt.Errorf("%s is not a regular file", path)
+	}
+	e := entry{name: name, path: path, mode: info.Mode().Perm(), modTime: info.ModTime()}
+	if executable {
+		e.mode = 0777
+	}
+	if opts.reproducible {
+		e.modTime = opts.modTime
+		e.mode = 0644
+		if executable || info.Mode()&0111 != 0 {
+			e.mode = 0755
+		}
+	}
+	return e, nil
+}
+
+// symlinkEntry returns the entry for a symlink named name that points to target.
+func symlinkEntry(name, target string, opts zipOptions) entry {
+	e := entry{name: name, link: target, mode: 0755 | os.ModeSymlink, modTime: time.Now()}
+	if opts.reproducible {
+		e.modTime = opts.modTime
+	}
+	return e
+}
+
+// writeEntries writes the entries to w as a zip archive, sorted by name.
+func writeEntries(w io.Writer, entries []entry) error {
+	sorted := make([]entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].name < sorted[j].name })
+
+	zipWriter := zip.NewWriter(w)
+	for i, e := range sorted {
+		if i > 0 && e.name == sorted[i-1].name {
+			return fmt.Errorf("duplicate path in zip: %s", e.name)
+		}
+		if err := writeEntry(zipWriter, e); err != nil {
+			return fmt.Errorf("%s: %v", e.name, err)
+		}
+	}
+	return zipWriter.Close()
+}
+
+func writeEntry(writer *zip.Writer, e entry) error {
+	header := &zip.FileHeader{
+		Name:     e.name,
+		Method:   zip.Deflate,
+		Modified: e.modTime,
+	}
+	header.SetMode(e.mode) // also marks the entry as created on Unix, so Lambda honors the mode
+	w, err := writer.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	if e.mode&os.ModeSymlink != 0 {
+		_, err = io.WriteString(w, e.link)
+		return err
+	}
+
+	f, err := os.Open(e.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
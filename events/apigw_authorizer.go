@@ -0,0 +1,86 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package events
+
+import (
+	"fmt"
+	"strings"
+)
+
+// APIGatewayMethodARN is the parsed form of the MethodArn of a REST API authorizer request,
+// or the RouteArn of an HTTP API authorizer request, eg:
+//
+//	arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/GET/pets/1
+type APIGatewayMethodARN struct {
+	Partition string
+	Region    string
+	AccountID string
+	APIID     string
+	Stage     string
+	Method    string
+	Path      string // Path includes the leading slash, and is empty when the ARN ends at the method
+}
+
+// ParseAPIGatewayMethodARN parses the MethodArn or RouteArn of an authorizer request.
+func ParseAPIGatewayMethodARN(arn string) (APIGatewayMethodARN, error) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[2] != "execute-api" {
+		return APIGatewayMethodARN{}, fmt.Errorf("invalid API Gateway method ARN %q", arn)
+	}
+	resource := strings.SplitN(parts[5], "/", 4)
+	if len(resource) < 3 {
+		return APIGatewayMethodARN{}, fmt.Errorf("invalid API Gateway method ARN %q: expected {api-id}/{stage}/{method}/{path}", arn)
+	}
+	parsed := APIGatewayMethodARN{
+		Partition: parts[1],
+		Region:    parts[3],
+		AccountID: parts[4],
+		APIID:     resource[0],
+		Stage:     resource[1],
+		Method:    resource[2],
+	}
+	if len(resource) == 4 {
+		parsed.Path = "/" + resource[3]
+	}
+	return parsed, nil
+}
+
+// String returns the ARN.
+func (a APIGatewayMethodARN) String() string {
+	return fmt.Sprintf("arn:%s:execute-api:%s:%s:%s/%s/%s%s", a.Partition, a.Region, a.AccountID, a.APIID, a.Stage, a.Method, a.Path)
+}
+
+// Resource returns the ARN for another method and path of the same API and stage.
+// Either may be "*" to match all methods or paths, eg: Resource("*", "*") matches the whole stage.
+func (a APIGatewayMethodARN) Resource(method, path string) string {
+	a.Method = method
+	a.Path = path
+	if a.Path != "" && !strings.HasPrefix(a.Path, "/") {
+		a.Path = "/" + a.Path
+	}
+	return a.String()
+}
+
+// NewAPIGatewayCustomAuthorizerResponse returns a REST API authorizer response with a policy made of the statements.
+//
+// Usage:
+//
+//	arn, err := events.ParseAPIGatewayMethodARN(request.MethodArn)
+//	...
+//	return events.NewAPIGatewayCustomAuthorizerResponse("user",
+//	        events.NewAllowStatement("execute-api:Invoke").On(arn.Resource("GET", "/pets/*")),
+//	), nil
+func NewAPIGatewayCustomAuthorizerResponse(principalID string, statements ...IAMPolicyStatement) *APIGatewayCustomAuthorizerResponse {
+	return &APIGatewayCustomAuthorizerResponse{
+		PrincipalID:    principalID,
+		PolicyDocument: APIGatewayCustomAuthorizerPolicy(NewIAMPolicyDocument(statements...)),
+	}
+}
+
+// NewAPIGatewayV2CustomAuthorizerIAMPolicyResponse returns an HTTP API authorizer response with a policy made of the statements.
+func NewAPIGatewayV2CustomAuthorizerIAMPolicyResponse(principalID string, statements ...IAMPolicyStatement) *APIGatewayV2CustomAuthorizerIAMPolicyResponse {
+	return &APIGatewayV2CustomAuthorizerIAMPolicyResponse{
+		PrincipalID:    principalID,
+		PolicyDocument: APIGatewayCustomAuthorizerPolicy(NewIAMPolicyDocument(statements...)),
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package events
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIGatewayMethodARN(t *testing.T) {
+	for arn, expected := range map[string]APIGatewayMethodARN{
+		"arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/GET/pets/1": {
+			Partition: "aws", Region: "us-east-1", AccountID: "123456789012", APIID: "abcdef1234", Stage: "prod", Method: "GET", Path: "/pets/1",
+		},
+		"arn:aws-cn:execute-api:cn-north-1:123456789012:abcdef1234/$default/POST/": {
+			Partition: "aws-cn", Region: "cn-north-1", AccountID: "123456789012", APIID: "abcdef1234", Stage: "$default", Method: "POST", Path: "/",
+		},
+		"arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/$connect": {
+			Partition: "aws", Region: "us-east-1", AccountID: "123456789012", APIID: "abcdef1234", Stage: "prod", Method: "$connect",
+		},
+	} {
+		parsed, err := ParseAPIGatewayMethodARN(arn)
+		require.NoError(t, err)
+		assert.Equal(t, expected, parsed)
+		assert.Equal(t, arn, parsed.String())
+	}
+
+	for _, arn := range []string{
+		"",
+		"arn:aws:lambda:us-east-1:123456789012:function:hello",
+		"arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod",
+	} {
+		_, err := ParseAPIGatewayMethodARN(arn)
+		assert.Error(t, err, arn)
+	}
+}
+
+func TestAPIGatewayMethodARNResource(t *testing.T) {
+	arn, err := ParseAPIGatewayMethodARN("arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/GET/pets/1")
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/*/*", arn.Resource("*", "*"))
+	assert.Equal(t, "arn:aws:execute-api:us-east-1:123456789012:abcdef1234/prod/POST/pets/*", arn.Resource("POST", "pets/*"))
+	assert.Equal(t, "/pets/1", arn.Path, "Resource must not modify the receiver")
+}
+
+func TestIAMPolicyStatementBuilder(t *testing.T) {
+	base := NewAllowStatement("execute-api:Invoke").On("arn:1")
+	statement := base.On("arn:2").When("IpAddress", "aws:SourceIp", "203.0.113.0/24").WithSid("AllowOffice")
+	assert.Equal(t, []string{"arn:1"}, base.Resource, "builders must not modify the receiver")
+	assert.Nil(t, base.Condition)
+
+	response := NewAPIGatewayCustomAuthorizerResponse("user", statement, NewDenyStatement("*").Except("arn:3"))
+	b, err := json.Marshal(response)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"principalId": "user",
+		"policyDocument": {
+			"Version": "2012-10-17",
+			"Statement": [
+				{
+					"Sid": "AllowOffice",
+					"Effect": "Allow",
+					"Action": ["execute-api:Invoke"],
+					"Resource": ["arn:1", "arn:2"],
+					"Condition": {"IpAddress": {"aws:SourceIp": ["203.0.113.0/24"]}}
+				},
+				{
+					"Effect": "Deny",
+					"Action": ["*"],
+					"NotResource": ["arn:3"]
+				}
+			]
+		}
+	}`, string(b))
+}
+
+func TestIAMPolicyStatementEncoding(t *testing.T) {
+	b, err := json.Marshal(IAMPolicyStatement{Effect: "Allow", Action: []string{"execute-api:Invoke"}, Resource: []string{"arn:1"}})
+	require.NoError(t, err)
+	assert.Equal(t, `{"Action":["execute-api:Invoke"],"Effect":"Allow","Resource":["arn:1"]}`, string(b))
+
+	var statement IAMPolicyStatement
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"Effect": "Allow",
+		"Principal": "*",
+		"NotPrincipal": {"AWS": "arn:aws:iam::123456789012:root"},
+		"Action": ["s3:GetObject"],
+		"Resource": ["arn:aws:s3:::bucket/*"],
+		"Condition": {"Bool": {"aws:SecureTransport": "true"}, "IpAddress": {"aws:SourceIp": ["203.0.113.0/24"]}}
+	}`), &statement))
+	assert.Equal(t, "*", statement.Principal)
+	assert.Equal(t, map[string]interface{}{"AWS": "arn:aws:iam::123456789012:root"}, statement.NotPrincipal)
+	assert.Equal(t, IAMPolicyConditionValues{"true"}, statement.Condition["Bool"]["aws:SecureTransport"])
+	assert.Equal(t, IAMPolicyConditionValues{"203.0.113.0/24"}, statement.Condition["IpAddress"]["aws:SourceIp"])
+}
+
+func TestNewAPIGatewayV2CustomAuthorizerIAMPolicyResponse(t *testing.T) {
+	response := NewAPIGatewayV2CustomAuthorizerIAMPolicyResponse("user", NewAllowStatement("execute-api:Invoke").On("*"))
+	assert.Equal(t, "user", response.PrincipalID)
+	assert.Equal(t, IAMPolicyVersion, response.PolicyDocument.Version)
+	assert.Len(t, response.PolicyDocument.Statement, 1)
+}
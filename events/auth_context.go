@@ -0,0 +1,219 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// AuthorizerContext is the identity of the caller, normalized across the request types that carry one.
+// See AuthContext.
+type AuthorizerContext struct {
+	// Principal identifies the caller. It is the principalId of a Lambda authorizer, the "sub" claim of a JWT,
+	// or the ARN of an IAM caller.
+	Principal string
+	// Claims are the claims of a JWT or Amazon Cognito user pool authorizer.
+	// Values that are not strings are JSON encoded.
+	Claims map[string]string
+	// Scopes are the OAuth scopes granted to the caller.
+	Scopes []string
+	// IAM is set when the caller was authorized with AWS IAM.
+	IAM *AuthorizerContextIAMIdentity
+	// Lambda is the context returned by a Lambda authorizer.
+	Lambda map[string]interface{}
+}
+
+// AuthorizerContextIAMIdentity is the IAM identity of a caller authorized with AWS IAM.
+type AuthorizerContextIAMIdentity struct {
+	AccessKey             string
+	AccountID             string
+	CallerID              string
+	UserARN               string
+	UserID                string
+	CognitoIdentityID     string
+	CognitoIdentityPoolID string
+}
+
+// AuthContext returns the identity of the caller of an HTTP request.
+// The request may be an APIGatewayProxyRequest, APIGatewayV2HTTPRequest, LambdaFunctionURLRequest or ALBTargetGroupRequest,
+// or a pointer to one. An error is returned for any other type.
+//
+// For ALB requests, the claims are read from the x-amzn-oidc-data header added by an authenticate-oidc or
+// authenticate-cognito listener rule. The signature of the header is not verified.
+func AuthContext(request interface{}) (*AuthorizerContext, error) {
+	switch r := request.(type) {
+	case APIGatewayProxyRequest:
+		return restAPIAuthContext(&r), nil
+	case *APIGatewayProxyRequest:
+		return restAPIAuthContext(r), nil
+	case APIGatewayV2HTTPRequest:
+		return httpAPIAuthContext(&r), nil
+	case *APIGatewayV2HTTPRequest:
+		return httpAPIAuthContext(r), nil
+	case LambdaFunctionURLRequest:
+		return functionURLAuthContext(&r), nil
+	case *LambdaFunctionURLRequest:
+		return functionURLAuthContext(r), nil
+	case ALBTargetGroupRequest:
+		return albAuthContext(&r), nil
+	case *ALBTargetGroupRequest:
+		return albAuthContext(r), nil
+	}
+	return nil, fmt.Errorf("events: AuthContext does not support requests of type %T", request)
+}
+
+func restAPIAuthContext(r *APIGatewayProxyRequest) *AuthorizerContext {
+	auth := &AuthorizerContext{}
+	identity := r.RequestContext.Identity
+	if identity.UserArn != "" || identity.AccessKey != "" {
+		auth.IAM = &AuthorizerContextIAMIdentity{
+			AccessKey:             identity.AccessKey,
+			AccountID:             identity.AccountID,
+			CallerID:              identity.Caller,
+			UserARN:               identity.UserArn,
+			UserID:                identity.User,
+			CognitoIdentityID:     identity.CognitoIdentityID,
+			CognitoIdentityPoolID: identity.CognitoIdentityPoolID,
+		}
+		auth.Principal = identity.UserArn
+	}
+	authorizer := r.RequestContext.Authorizer
+	if claims, ok := authorizer["claims"].(map[string]interface{}); ok {
+		// Amazon Cognito user pool authorizer
+		auth.Claims = stringClaims(claims)
+		auth.Principal = auth.Claims["sub"]
+		auth.Scopes = scopes(auth.Claims)
+	} else if len(authorizer) > 0 {
+		// Lambda authorizer
+		auth.Lambda = authorizer
+		if principalID, ok := authorizer["principalId"].(string); ok {
+			auth.Principal = principalID
+		}
+	}
+	return auth
+}
+
+func httpAPIAuthContext(r *APIGatewayV2HTTPRequest) *AuthorizerContext {
+	auth := &AuthorizerContext{}
+	authorizer := r.RequestContext.Authorizer
+	if authorizer == nil {
+		return auth
+	}
+	if authorizer.IAM != nil {
+		auth.IAM = &AuthorizerContextIAMIdentity{
+			AccessKey:             authorizer.IAM.AccessKey,
+			AccountID:             authorizer.IAM.AccountID,
+			CallerID:              authorizer.IAM.CallerID,
+			UserARN:               authorizer.IAM.UserARN,
+			UserID:                authorizer.IAM.UserID,
+			CognitoIdentityID:     authorizer.IAM.CognitoIdentity.IdentityID,
+			CognitoIdentityPoolID: authorizer.IAM.CognitoIdentity.IdentityPoolID,
+		}
+		auth.Principal = authorizer.IAM.UserARN
+	}
+	if authorizer.JWT != nil {
+		auth.Claims = authorizer.JWT.Claims
+		auth.Principal = authorizer.JWT.Claims["sub"]
+		auth.Scopes = authorizer.JWT.Scopes
+		if len(auth.Scopes) == 0 {
+			auth.Scopes = scopes(auth.Claims)
+		}
+	}
+	if authorizer.Lambda != nil {
+		auth.Lambda = authorizer.Lambda
+		if principalID, ok := authorizer.Lambda["principalId"].(string); ok {
+			auth.Principal = principalID
+		}
+	}
+	return auth
+}
+
+func functionURLAuthContext(r *LambdaFunctionURLRequest) *AuthorizerContext {
+	auth := &AuthorizerContext{}
+	if r.RequestContext.Authorizer != nil && r.RequestContext.Authorizer.IAM != nil {
+		iam := r.RequestContext.Authorizer.IAM
+		if *iam != (LambdaFunctionURLRequestContextAuthorizerIAMDescription{}) {
+			auth.IAM = &AuthorizerContextIAMIdentity{
+				AccessKey: iam.AccessKey,
+				AccountID: iam.AccountID,
+				CallerID:  iam.CallerID,
+				UserARN:   iam.UserARN,
+				UserID:    iam.UserID,
+			}
+			auth.Principal = iam.UserARN
+		}
+	}
+	return auth
+}
+
+func albAuthContext(r *ALBTargetGroupRequest) *AuthorizerContext {
+	auth := &AuthorizerContext{}
+	auth.Principal = albHeader(r, "x-amzn-oidc-identity")
+	if data := albHeader(r, "x-amzn-oidc-data"); data != "" {
+		if claims, err := UnverifiedJWTClaims(data); err == nil {
+			auth.Claims = stringClaims(claims)
+			if auth.Principal == "" {
+				auth.Principal = auth.Claims["sub"]
+			}
+			auth.Scopes = scopes(auth.Claims)
+		}
+	}
+	return auth
+}
+
+func albHeader(r *ALBTargetGroupRequest, name string) string {
+	for k, v := range r.Headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	for k, v := range r.MultiValueHeaders {
+		if strings.EqualFold(k, name) && len(v) > 0 {
+			return v[0]
+		}
+	}
+	return ""
+}
+
+// UnverifiedJWTClaims decodes the claims of a JWT without verifying its signature.
+// Only use it for tokens that were already verified, such as those passed on by API Gateway or an ALB.
+func UnverifiedJWTClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("events: malformed JWT: expected 3 parts, got %d", len(parts))
+	}
+	// ALB pads the segments of the x-amzn-oidc-data token, which the JWT spec does not allow
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, fmt.Errorf("events: malformed JWT payload: %v", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, fmt.Errorf("events: malformed JWT payload: %v", err)
+	}
+	return claims, nil
+}
+
+func stringClaims(claims map[string]interface{}) map[string]string {
+	out := make(map[string]string, len(claims))
+	for k, v := range claims {
+		if s, ok := v.(string); ok {
+			out[k] = s
+			continue
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		out[k] = string(b)
+	}
+	return out
+}
+
+// scopes splits the space separated "scope" claim of OAuth 2.0 access tokens.
+func scopes(claims map[string]string) []string {
+	return strings.Fields(claims["scope"])
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil" //nolint: staticcheck
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readTestEvent(t *testing.T, file string, event interface{}) {
+	inputJSON, err := ioutil.ReadFile("./testdata/" + file)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(inputJSON, event))
+}
+
+func TestAuthContextRESTAPI(t *testing.T) {
+	var request APIGatewayProxyRequest
+	readTestEvent(t, "apigw-request.json", &request)
+	auth, err := AuthContext(request)
+	require.NoError(t, err)
+	assert.Equal(t, "admin", auth.Principal)
+	assert.Equal(t, "Exata", auth.Lambda["clientName"])
+	require.NotNil(t, auth.IAM)
+	assert.Equal(t, "theUserArn", auth.IAM.UserARN)
+	assert.Equal(t, "theCognitoIdentityId", auth.IAM.CognitoIdentityID)
+
+	request.RequestContext.Authorizer = map[string]interface{}{
+		"claims": map[string]interface{}{"sub": "user-1", "scope": "read write", "email_verified": true},
+	}
+	auth, err = AuthContext(&request)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", auth.Principal)
+	assert.Equal(t, "true", auth.Claims["email_verified"])
+	assert.Equal(t, []string{"read", "write"}, auth.Scopes)
+	assert.Nil(t, auth.Lambda)
+}
+
+func TestAuthContextHTTPAPI(t *testing.T) {
+	var jwt, iam, lambda APIGatewayV2HTTPRequest
+	readTestEvent(t, "apigw-v2-request-jwt-authorizer.json", &jwt)
+	readTestEvent(t, "apigw-v2-request-iam.json", &iam)
+	readTestEvent(t, "apigw-v2-request-lambda-authorizer.json", &lambda)
+
+	auth, err := AuthContext(&jwt)
+	require.NoError(t, err)
+	assert.Equal(t, "value1", auth.Claims["claim1"])
+	assert.Equal(t, []string{"scope1", "scope2"}, auth.Scopes)
+	assert.Nil(t, auth.IAM)
+
+	auth, err = AuthContext(iam)
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::1234567890:user/Admin", auth.Principal)
+	require.NotNil(t, auth.IAM)
+	assert.Equal(t, "us-east-1:4f291106-8703-466b-8f2b-3ecee1ca56ce", auth.IAM.CognitoIdentityPoolID)
+
+	auth, err = AuthContext(lambda)
+	require.NoError(t, err)
+	assert.Equal(t, "value", auth.Lambda["key"])
+
+	auth, err = AuthContext(APIGatewayV2HTTPRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, &AuthorizerContext{}, auth)
+}
+
+func TestAuthContextFunctionURL(t *testing.T) {
+	var request LambdaFunctionURLRequest
+	readTestEvent(t, "lambda-urls-request.json", &request)
+	auth, err := AuthContext(request)
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::111122223333:user/example-user", auth.Principal)
+	assert.Equal(t, "111122223333", auth.IAM.AccountID)
+}
+
+func TestAuthContextALB(t *testing.T) {
+	payload := base64.URLEncoding.EncodeToString([]byte(`{"sub":"user-1","email":"user@example.com"}`))
+	request := ALBTargetGroupRequest{
+		MultiValueHeaders: map[string][]string{
+			"x-amzn-oidc-data": {"eyJhbGciOiJFUzI1NiJ9." + payload + ".c2ln"},
+		},
+	}
+	auth, err := AuthContext(&request)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", auth.Principal)
+	assert.Equal(t, "user@example.com", auth.Claims["email"])
+}
+
+func TestAuthContextUnsupported(t *testing.T) {
+	_, err := AuthContext(SQSEvent{})
+	assert.EqualError(t, err, "events: AuthContext does not support requests of type events.SQSEvent")
+}
+
+func TestUnverifiedJWTClaims(t *testing.T) {
+	_, err := UnverifiedJWTClaims("not-a-jwt")
+	assert.Error(t, err)
+	_, err = UnverifiedJWTClaims("a.!!!.c")
+	assert.Error(t, err)
+}
@@ -35,16 +35,11 @@ func generatePolicy(principalID, effect, resource string) *events.APIGatewayCust
 	authResponse := &events.APIGatewayCustomAuthorizerResponse{PrincipalID: principalID}
 
 	if effect != "" && resource != "" {
-		authResponse.PolicyDocument = events.APIGatewayCustomAuthorizerPolicy{
-			Version: "2012-10-17",
-			Statement: []events.IAMPolicyStatement{
-				{
-					Action:   []string{"execute-api:Invoke"},
-					Effect:   effect,
-					Resource: []string{resource},
-				},
-			},
+		statement := events.NewAllowStatement("execute-api:Invoke")
+		if effect == "Deny" {
+			statement = events.NewDenyStatement("execute-api:Invoke")
 		}
+		authResponse.PolicyDocument = events.APIGatewayCustomAuthorizerPolicy(events.NewIAMPolicyDocument(statement.On(resource)))
 	}
 
 	authResponse.Context = map[string]interface{}{
@@ -1,5 +1,12 @@
 package events
 
+import (
+	"encoding/json"
+)
+
+// IAMPolicyVersion is the current version of the IAM policy language.
+const IAMPolicyVersion = "2012-10-17"
+
 // IAMPolicyDocument represents an IAM policy document.
 type IAMPolicyDocument struct {
 	Version   string
@@ -7,8 +14,108 @@ type IAMPolicyDocument struct {
 }
 
 // IAMPolicyStatement represents one statement from IAM policy with action, effect and resource.
+// Use NewAllowStatement or NewDenyStatement to build a statement, eg:
+//
+//	events.NewAllowStatement("execute-api:Invoke").On(methodArn)
 type IAMPolicyStatement struct {
 	Action   []string
 	Effect   string
 	Resource []string
+	Sid      string `json:",omitempty"`
+	// Principal and NotPrincipal are either "*" or a map of principal types to one or more IDs,
+	// eg: {"AWS": "arn:aws:iam::123456789012:root"}.
+	Principal    interface{}                                    `json:",omitempty"`
+	NotPrincipal interface{}                                    `json:",omitempty"`
+	NotAction    []string                                       `json:",omitempty"`
+	NotResource  []string                                       `json:",omitempty"`
+	Condition    map[string]map[string]IAMPolicyConditionValues `json:",omitempty"`
+}
+
+// MarshalJSON leaves out Action and Resource when they are empty and the statement uses NotAction or NotResource
+// instead, as a statement can't have both. Other statements are encoded with all of their fields.
+func (s IAMPolicyStatement) MarshalJSON() ([]byte, error) {
+	type statement IAMPolicyStatement
+	if s.NotAction == nil && s.NotResource == nil {
+		return json.Marshal(statement(s))
+	}
+	type notStatement struct {
+		Action       []string `json:",omitempty"`
+		Effect       string
+		Resource     []string                                       `json:",omitempty"`
+		Sid          string                                         `json:",omitempty"`
+		Principal    interface{}                                    `json:",omitempty"`
+		NotPrincipal interface{}                                    `json:",omitempty"`
+		NotAction    []string                                       `json:",omitempty"`
+		NotResource  []string                                       `json:",omitempty"`
+		Condition    map[string]map[string]IAMPolicyConditionValues `json:",omitempty"`
+	}
+	return json.Marshal(notStatement(s))
+}
+
+// IAMPolicyConditionValues are the values of a condition key, which a policy can give as a single string or a list.
+type IAMPolicyConditionValues []string
+
+// UnmarshalJSON accepts a single string as well as a list of strings.
+func (v *IAMPolicyConditionValues) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err == nil {
+		*v = IAMPolicyConditionValues{value}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(v))
+}
+
+// NewIAMPolicyDocument returns a policy document with the current version of the IAM policy language.
+func NewIAMPolicyDocument(statements ...IAMPolicyStatement) IAMPolicyDocument {
+	return IAMPolicyDocument{
+		Version:   IAMPolicyVersion,
+		Statement: statements,
+	}
+}
+
+// NewAllowStatement returns a statement that allows the actions.
+func NewAllowStatement(actions ...string) IAMPolicyStatement {
+	return IAMPolicyStatement{Effect: "Allow", Action: actions}
+}
+
+// NewDenyStatement returns a statement that denies the actions.
+func NewDenyStatement(actions ...string) IAMPolicyStatement {
+	return IAMPolicyStatement{Effect: "Deny", Action: actions}
+}
+
+// On returns a copy of the statement that applies to the resources.
+func (s IAMPolicyStatement) On(resources ...string) IAMPolicyStatement {
+	s.Resource = append(append([]string(nil), s.Resource...), resources...)
+	return s
+}
+
+// Except returns a copy of the statement that applies to all resources except for the given resources.
+func (s IAMPolicyStatement) Except(resources ...string) IAMPolicyStatement {
+	s.NotResource = append(append([]string(nil), s.NotResource...), resources...)
+	return s
+}
+
+// When returns a copy of the statement with an additional condition, eg:
+//
+//	events.NewAllowStatement("execute-api:Invoke").On(methodArn).When("IpAddress", "aws:SourceIp", "203.0.113.0/24")
+func (s IAMPolicyStatement) When(operator, key string, values ...string) IAMPolicyStatement {
+	condition := make(map[string]map[string]IAMPolicyConditionValues, len(s.Condition)+1)
+	for op, keys := range s.Condition {
+		condition[op] = make(map[string]IAMPolicyConditionValues, len(keys))
+		for k, v := range keys {
+			condition[op][k] = v
+		}
+	}
+	if condition[operator] == nil {
+		condition[operator] = map[string]IAMPolicyConditionValues{}
+	}
+	condition[operator][key] = append(append([]string(nil), condition[operator][key]...), values...)
+	s.Condition = condition
+	return s
+}
+
+// WithSid returns a copy of the statement with the statement ID set.
+func (s IAMPolicyStatement) WithSid(sid string) IAMPolicyStatement {
+	s.Sid = sid
+	return s
 }
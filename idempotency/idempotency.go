@@ -0,0 +1,200 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package idempotency makes a lambda.Handler process each event once, even when the event source retries it.
+//
+// The handler is wrapped with NewHandler, which derives a key for each invoke, either from a field of the event
+// or from the request ID of the invoke. The first invoke with a key locks it in a Store until its deadline,
+// and stores its response when it succeeds. Later invokes with the same key return the stored response
+// without calling the handler, or fail with ErrInProgress while the first invoke is still running.
+//
+//	store := idempotency.NewMemoryStore()
+//	lambda.Start(idempotency.NewHandler(lambda.NewHandler(processOrder), store, idempotency.WithKeyPath("detail.orderId")))
+//
+// NewMemoryStore and NewFileStore are meant for tests and local runs, within a single process.
+// Functions with more than one execution environment need a Store backed by a shared database,
+// such as a DynamoDB table, with an atomic conditional write for Lock.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// DefaultExpiry is how long the response of an invoke is returned for duplicates, unless set with WithExpiry.
+const DefaultExpiry = time.Hour
+
+// defaultLockTimeout is how long a key is locked by an invoke without a deadline.
+const defaultLockTimeout = 15 * time.Minute
+
+// ErrInProgress is returned for an invoke with the same key as an invoke that has not completed yet.
+// The event source retries the invoke later, when the response of the first invoke is stored.
+var ErrInProgress = errors.New("idempotency: an invoke with the same key is in progress")
+
+// ErrMissingKey is returned when the key path set with WithKeyPath is not found in the event, or is null.
+var ErrMissingKey = errors.New("idempotency: the event does not have a value at the key path")
+
+type options struct {
+	keyPath []string
+	expiry  time.Duration
+	now     func() time.Time
+}
+
+// Option configures the handler returned by NewHandler.
+type Option func(*options)
+
+// WithKeyPath derives the key from the value at a path of the JSON event, instead of the request ID of the invoke.
+// The path is a list of object keys and array indexes separated by dots, such as "Records.0.messageId".
+// A dot or a backslash that is part of an object key is escaped with a backslash, such as `detail.order\.id`
+// for the "order.id" key.
+// Events with equal values at the path are duplicates, whatever the rest of the event is.
+func WithKeyPath(path string) Option {
+	return func(o *options) {
+		o.keyPath = splitKeyPath(path)
+	}
+}
+
+// splitKeyPath splits a path at the dots that are not escaped with a backslash.
+func splitKeyPath(path string) []string {
+	var names []string
+	var name strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			name.WriteByte(path[i])
+		case path[i] == '.':
+			names = append(names, name.String())
+			name.Reset()
+		default:
+			name.WriteByte(path[i])
+		}
+	}
+	return append(names, name.String())
+}
+
+// WithExpiry sets how long the response of an invoke is returned for duplicates.
+func WithExpiry(expiry time.Duration) Option {
+	return func(o *options) {
+		o.expiry = expiry
+	}
+}
+
+type handler struct {
+	handler lambda.Handler
+	store   Store
+	options
+}
+
+// NewHandler returns a Handler that calls h once for each key, and returns the stored response for duplicates.
+// When h returns an error, the key is unlocked so that the event can be retried.
+// When the response of h can't be stored, the failure is logged and the response is still returned,
+// and duplicates fail with ErrInProgress until the lock of the key expires.
+func NewHandler(h lambda.Handler, store Store, opts ...Option) lambda.Handler {
+	wrapped := &handler{
+		handler: h,
+		store:   store,
+		options: options{
+			expiry: DefaultExpiry,
+			now:    time.Now,
+		},
+	}
+	for _, opt := range opts {
+		opt(&wrapped.options)
+	}
+	return wrapped
+}
+
+func (h *handler) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	key, err := h.key(ctx, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	lockedUntil := h.now().Add(defaultLockTimeout)
+	if deadline, ok := ctx.Deadline(); ok {
+		lockedUntil = deadline
+	}
+	record, err := h.store.Lock(ctx, key, lockedUntil)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency: failed to lock key %s: %w", key, err)
+	}
+	if record != nil {
+		if record.Status == StatusCompleted {
+			return record.Response, nil
+		}
+		return nil, ErrInProgress
+	}
+
+	response, err := h.handler.Invoke(ctx, payload)
+	if err != nil {
+		if unlockErr := h.store.Unlock(ctx, key); unlockErr != nil {
+			return nil, fmt.Errorf("%w (idempotency: failed to unlock key %s: %v)", err, key, unlockErr)
+		}
+		return nil, err
+	}
+	if err := h.store.Complete(ctx, key, response, h.now().Add(h.expiry)); err != nil {
+		// the handler already ran, so failing the invoke would only get the event retried once the lock expires
+		log.Printf("idempotency: failed to store the response for key %s: %v", key, err)
+	}
+	return response, nil
+}
+
+// key returns the key of the invoke, which is a hash of the value at the key path,
+// or the request ID of the invoke if no key path is set.
+func (h *handler) key(ctx context.Context, payload []byte) (string, error) {
+	if h.keyPath == nil {
+		lc, ok := lambdacontext.FromContext(ctx)
+		if !ok || lc.AwsRequestID == "" {
+			return "", errors.New("idempotency: the context does not have a request ID")
+		}
+		return lc.AwsRequestID, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var event interface{}
+	if err := decoder.Decode(&event); err != nil {
+		return "", fmt.Errorf("idempotency: failed to decode the event: %w", err)
+	}
+	value := lookup(event, h.keyPath)
+	if value == nil {
+		return "", ErrMissingKey
+	}
+	// maps are encoded with sorted keys, so equal values have the same hash
+	b, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("idempotency: failed to encode the key: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+func lookup(value interface{}, path []string) interface{} {
+	for _, name := range path {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value = v[name]
+		case []interface{}:
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil
+			}
+			value = v[i]
+		default:
+			return nil
+		}
+	}
+	return value
+}
@@ -0,0 +1,198 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orderEvent struct {
+	Detail struct {
+		OrderID string `json:"orderId"`
+	} `json:"detail"`
+}
+
+func invokeContext(t *testing.T, requestID string) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(time.Minute))
+	t.Cleanup(cancel)
+	return lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{AwsRequestID: requestID})
+}
+
+func TestHandlerReturnsStoredResponseForDuplicates(t *testing.T) {
+	calls := 0
+	h := NewHandler(lambda.NewHandler(func(event orderEvent) (string, error) {
+		calls++
+		return fmt.Sprintf("processed %s #%d", event.Detail.OrderID, calls), nil
+	}), NewMemoryStore(), WithKeyPath("detail.orderId"))
+
+	first, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{"detail":{"orderId":"a"},"attempt":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"processed a #1"`, string(first))
+
+	duplicate, err := h.Invoke(invokeContext(t, "request-2"), []byte(`{"detail":{"orderId":"a"},"attempt":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, first, duplicate)
+
+	_, err = h.Invoke(invokeContext(t, "request-3"), []byte(`{"detail":{"orderId":"b"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestHandlerKeyedOnRequestID(t *testing.T) {
+	calls := 0
+	h := NewHandler(lambda.NewHandler(func() (int, error) {
+		calls++
+		return calls, nil
+	}), NewMemoryStore())
+
+	for _, requestID := range []string{"request-1", "request-1", "request-2"} {
+		_, err := h.Invoke(invokeContext(t, requestID), []byte(`{}`))
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, calls)
+
+	_, err := h.Invoke(context.Background(), []byte(`{}`))
+	assert.EqualError(t, err, "idempotency: the context does not have a request ID")
+}
+
+func TestHandlerInProgress(t *testing.T) {
+	store := NewMemoryStore()
+	var h lambda.Handler
+	var nested error
+	h = NewHandler(lambda.NewHandler(func(ctx context.Context) (string, error) {
+		// a duplicate arrives while the first invoke is running
+		_, nested = h.Invoke(ctx, []byte(`{}`))
+		return "done", nil
+	}), store)
+
+	response, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"done"`, string(response))
+	assert.Equal(t, ErrInProgress, nested)
+}
+
+func TestHandlerLockExpiresWithDeadline(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	ctx, cancel := context.WithDeadline(lambdacontext.NewContext(context.Background(), &lambdacontext.LambdaContext{AwsRequestID: "request-1"}), now.Add(time.Minute))
+	defer cancel()
+	record, err := store.Lock(ctx, "request-1", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, record)
+
+	h := NewHandler(lambda.NewHandler(func() (string, error) { return "retried", nil }), store)
+	_, err = h.Invoke(ctx, []byte(`{}`))
+	assert.Equal(t, ErrInProgress, err)
+
+	// the invoke holding the lock timed out without completing
+	store.now = func() time.Time { return now.Add(2 * time.Minute) }
+	response, err := h.Invoke(ctx, []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"retried"`, string(response))
+}
+
+func TestHandlerErrorUnlocks(t *testing.T) {
+	fail := true
+	h := NewHandler(lambda.NewHandler(func() (string, error) {
+		if fail {
+			return "", errors.New("downstream unavailable")
+		}
+		return "ok", nil
+	}), NewMemoryStore())
+
+	_, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	assert.EqualError(t, err, "downstream unavailable")
+
+	fail = false
+	response, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"ok"`, string(response))
+}
+
+type failingCompleteStore struct {
+	*MemoryStore
+}
+
+func (s failingCompleteStore) Complete(context.Context, string, []byte, time.Time) error {
+	return errors.New("table unavailable")
+}
+
+func TestHandlerCompleteFailureReturnsResponse(t *testing.T) {
+	calls := 0
+	h := NewHandler(lambda.NewHandler(func() (string, error) {
+		calls++
+		return "charged", nil
+	}), failingCompleteStore{NewMemoryStore()})
+
+	response, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"charged"`, string(response))
+
+	_, err = h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	assert.Equal(t, ErrInProgress, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestHandlerExpiry(t *testing.T) {
+	store := NewMemoryStore()
+	now := time.Now()
+	store.now = func() time.Time { return now }
+	calls := 0
+	h := NewHandler(lambda.NewHandler(func() (int, error) {
+		calls++
+		return calls, nil
+	}), store, WithExpiry(time.Minute))
+	h.(*handler).now = store.now
+
+	_, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	require.NoError(t, err)
+	store.now = func() time.Time { return now.Add(2 * time.Minute) }
+	response, err := h.Invoke(invokeContext(t, "request-1"), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, "2", string(response))
+}
+
+func TestKey(t *testing.T) {
+	testCases := map[string]struct {
+		path     string
+		payload  string
+		sameAs   string
+		expected error
+	}{
+		"object field":           {path: "detail.orderId", payload: `{"detail":{"orderId":"a","other":1}}`, sameAs: `{"detail":{"orderId":"a"}}`},
+		"array index":            {path: "Records.1.messageId", payload: `{"Records":[{"messageId":"a"},{"messageId":"b"}]}`, sameAs: `{"Records":[{},{"messageId":"b"}]}`},
+		"object value":           {path: "detail", payload: `{"detail":{"b":2,"a":1}}`, sameAs: `{"detail":{"a":1,"b":2}}`},
+		"missing field":          {path: "detail.orderId", payload: `{"detail":{}}`, expected: ErrMissingKey},
+		"null field":             {path: "detail", payload: `{"detail":null}`, expected: ErrMissingKey},
+		"index out of range":     {path: "Records.2", payload: `{"Records":[]}`, expected: ErrMissingKey},
+		"field of a string":      {path: "detail.orderId", payload: `{"detail":"a"}`, expected: ErrMissingKey},
+		"large numbers are kept": {path: "id", payload: `{"id":12345678901234567890}`, sameAs: `{"id":12345678901234567890}`},
+		"escaped dot":            {path: `detail.order\.id`, payload: `{"detail":{"order.id":"a"}}`, sameAs: `{"detail":{"order.id":"a","order":{"id":"b"}}}`},
+		"escaped dot is a key":   {path: `detail.order\.id`, payload: `{"detail":{"order":{"id":"a"}}}`, expected: ErrMissingKey},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := NewHandler(nil, nil, WithKeyPath(testCase.path)).(*handler)
+			key, err := h.key(context.Background(), []byte(testCase.payload))
+			if testCase.expected != nil {
+				assert.Equal(t, testCase.expected, err)
+				return
+			}
+			require.NoError(t, err)
+			sameKey, err := h.key(context.Background(), []byte(testCase.sameAs))
+			require.NoError(t, err)
+			assert.Equal(t, key, sameKey)
+		})
+	}
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil" //nolint: staticcheck
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Status is the status of the invoke that locked a key.
+type Status string
+
+const (
+	StatusInProgress Status = "IN_PROGRESS"
+	StatusCompleted  Status = "COMPLETED"
+)
+
+// Record is the state of a key in a Store.
+type Record struct {
+	Key       string    `json:"key"`
+	Status    Status    `json:"status"`
+	Response  []byte    `json:"response,omitempty"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Store keeps the records of the keys of invokes. Lock must be atomic,
+// so that only one of the invokes with the same key runs the handler.
+type Store interface {
+	// Lock creates an in-progress record for the key that expires at lockedUntil,
+	// and returns nil if there is no record for the key, or if it has expired.
+	// Otherwise it returns the existing record.
+	Lock(ctx context.Context, key string, lockedUntil time.Time) (*Record, error)
+
+	// Complete stores the response for the key, which is returned for duplicates until expiresAt.
+	Complete(ctx context.Context, key string, response []byte, expiresAt time.Time) error
+
+	// Unlock deletes the record for the key, after the handler failed.
+	Unlock(ctx context.Context, key string) error
+}
+
+// MemoryStore is a Store that keeps the records in memory.
+// It only deduplicates the invokes of a single execution environment.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+	now     func() time.Time
+}
+
+// NewMemoryStore returns an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record), now: time.Now}
+}
+
+// Lock implements Store.
+func (s *MemoryStore) Lock(_ context.Context, key string, lockedUntil time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[key]; ok && s.now().Before(record.ExpiresAt) {
+		return &record, nil
+	}
+	s.records[key] = Record{Key: key, Status: StatusInProgress, ExpiresAt: lockedUntil}
+	return nil, nil
+}
+
+// Complete implements Store.
+func (s *MemoryStore) Complete(_ context.Context, key string, response []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records[key] = Record{
+		Key:       key,
+		Status:    StatusCompleted,
+		Response:  append([]byte(nil), response...),
+		ExpiresAt: expiresAt,
+	}
+	return nil
+}
+
+// Unlock implements Store.
+func (s *MemoryStore) Unlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, key)
+	return nil
+}
+
+// FileStore is a Store that keeps each record in a JSON file in a directory,
+// so that the records outlive the process in local runs and tests.
+//
+// FileStore is for local use only. Lock is only atomic within a single process, so processes sharing
+// the directory may both run the handler for the same key. Keys are not namespaced either,
+// so each function needs its own directory.
+type FileStore struct {
+	dir string
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewFileStore returns a FileStore that keeps the records in dir, which is created if needed.
+// The directory must not be shared with other functions or processes.
+func NewFileStore(dir string) (*FileStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &FileStore{dir: dir, now: time.Now}, nil
+}
+
+func (s *FileStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (s *FileStore) read(key string) (*Record, error) {
+	b, err := ioutil.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var record Record
+	if err := json.Unmarshal(b, &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// write replaces the record atomically, so that a concurrent reader never sees a partial file.
+func (s *FileStore) write(record Record) error {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.dir, ".record-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(record.Key))
+}
+
+// Lock implements Store.
+func (s *FileStore) Lock(_ context.Context, key string, lockedUntil time.Time) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil && s.now().Before(record.ExpiresAt) {
+		return record, nil
+	}
+	return nil, s.write(Record{Key: key, Status: StatusInProgress, ExpiresAt: lockedUntil})
+}
+
+// Complete implements Store.
+func (s *FileStore) Complete(_ context.Context, key string, response []byte, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.write(Record{Key: key, Status: StatusCompleted, Response: response, ExpiresAt: expiresAt})
+}
+
+// Unlock implements Store.
+func (s *FileStore) Unlock(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := os.Remove(s.path(key))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	now := time.Now()
+	fileStore, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	fileStore.now = func() time.Time { return now }
+	memoryStore := NewMemoryStore()
+	memoryStore.now = func() time.Time { return now }
+
+	stores := map[string]Store{
+		"memory": memoryStore,
+		"file":   fileStore,
+	}
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+
+			record, err := store.Lock(ctx, "key", now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.Nil(t, record)
+
+			record, err = store.Lock(ctx, "key", now.Add(time.Minute))
+			require.NoError(t, err)
+			require.NotNil(t, record)
+			assert.Equal(t, StatusInProgress, record.Status)
+
+			require.NoError(t, store.Complete(ctx, "key", []byte(`"response"`), now.Add(time.Hour)))
+			record, err = store.Lock(ctx, "key", now.Add(time.Minute))
+			require.NoError(t, err)
+			require.NotNil(t, record)
+			assert.Equal(t, StatusCompleted, record.Status)
+			assert.Equal(t, `"response"`, string(record.Response))
+			assert.True(t, now.Add(time.Hour).Equal(record.ExpiresAt))
+
+			require.NoError(t, store.Unlock(ctx, "key"))
+			require.NoError(t, store.Unlock(ctx, "key"))
+			record, err = store.Lock(ctx, "key", now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.Nil(t, record)
+
+			record, err = store.Lock(ctx, "expired", now.Add(-time.Second))
+			require.NoError(t, err)
+			assert.Nil(t, record)
+			record, err = store.Lock(ctx, "expired", now.Add(time.Minute))
+			require.NoError(t, err)
+			assert.Nil(t, record, "an expired record is replaced")
+		})
+	}
+}
+
+func TestFileStoreOutlivesTheStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	require.NoError(t, store.Complete(context.Background(), "key", []byte(`1`), time.Now().Add(time.Hour)))
+
+	reopened, err := NewFileStore(dir)
+	require.NoError(t, err)
+	record, err := reopened.Lock(context.Background(), "key", time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "1", string(record.Response))
+}
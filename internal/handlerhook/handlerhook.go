@@ -0,0 +1,15 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package handlerhook gives the other packages of this module access to the handlers created by the lambda package,
+// without adding to its public API.
+package handlerhook
+
+import (
+	"context"
+	"io"
+)
+
+// Unwrap returns the base context of a handler created by lambda.NewHandlerWithOptions,
+// and a function that invokes it, returning its response without reading it.
+// It reports false for other handlers. It is set by the lambda package.
+var Unwrap func(handler interface{}) (base context.Context, invoke func(ctx context.Context, payload []byte) (io.Reader, error), ok bool)
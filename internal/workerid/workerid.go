@@ -0,0 +1,20 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package workerid carries the index of the invoke loop handling an invoke in its context.
+// The lambda package sets it and the lambdacontext package reads it, so that user code cannot forge it.
+package workerid
+
+import "context"
+
+type key struct{}
+
+// NewContext returns a new Context that carries the index of the invoke loop, or worker, handling the invoke.
+func NewContext(parent context.Context, worker int) context.Context {
+	return context.WithValue(parent, key{}, worker)
+}
+
+// FromContext returns the index of the worker stored in ctx, if any.
+func FromContext(ctx context.Context) (int, bool) {
+	worker, ok := ctx.Value(key{}).(int)
+	return worker, ok
+}
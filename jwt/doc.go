@@ -0,0 +1,10 @@
+// Package jwt verifies JSON Web Tokens in Lambda authorizers.
+//
+// A Verifier checks the signature of a token against a KeySet, such as the JSON Web Key Set published by
+// an identity provider, and validates the expiry, issuer and audience claims.
+//
+// Requests that reach a function through an API Gateway JWT authorizer have already been verified,
+// use events.AuthContext to read their claims instead.
+//
+// See https://datatracker.ietf.org/doc/html/rfc7519
+package jwt
@@ -0,0 +1,244 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrKeyNotFound is returned by a KeySet that has no key with the requested ID.
+var ErrKeyNotFound = errors.New("jwt: key not found")
+
+// KeySet looks up the public key that signed a token by its key ID, the "kid" header of the token.
+type KeySet interface {
+	Key(ctx context.Context, kid string) (crypto.PublicKey, error)
+}
+
+// StaticKeys is a KeySet of fixed keys, mostly useful for tests.
+// The supported key types are *rsa.PublicKey and *ecdsa.PublicKey.
+type StaticKeys map[string]crypto.PublicKey
+
+// Key implements KeySet.
+func (k StaticKeys) Key(_ context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := k[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+// JWKS is a KeySet that fetches a JSON Web Key Set from a URL, such as
+// "https://cognito-idp.{region}.amazonaws.com/{userPoolId}/.well-known/jwks.json".
+// The keys are cached between invokes, and fetched again when a token references an unknown key ID.
+type JWKS struct {
+	url        string
+	client     *http.Client
+	minRefresh time.Duration
+
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	lastFetched time.Time
+	refresh     *jwksRefresh
+	now         func() time.Time
+}
+
+// jwksRefresh is a fetch of the key set in progress, which concurrent lookups of unknown keys wait for.
+type jwksRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// JWKSOption configures a JWKS.
+type JWKSOption func(*JWKS)
+
+// WithHTTPClient sets the HTTP client used to fetch the key set. The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) JWKSOption {
+	return func(j *JWKS) {
+		j.client = client
+	}
+}
+
+// WithMinRefreshInterval limits how often the key set is fetched again for unknown key IDs. The default is 5 minutes.
+func WithMinRefreshInterval(interval time.Duration) JWKSOption {
+	return func(j *JWKS) {
+		j.minRefresh = interval
+	}
+}
+
+// NewJWKS returns a KeySet backed by the JSON Web Key Set at url.
+func NewJWKS(url string, options ...JWKSOption) *JWKS {
+	j := &JWKS{
+		url:        url,
+		client:     http.DefaultClient,
+		minRefresh: 5 * time.Minute,
+		now:        time.Now,
+	}
+	for _, option := range options {
+		option(j)
+	}
+	return j
+}
+
+// Key implements KeySet.
+// The key set is fetched without holding the lock, so that lookups of cached keys don't wait for the network,
+// and concurrent lookups of unknown keys share a single fetch.
+func (j *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	j.mu.Lock()
+	if key, ok := j.keys[kid]; ok {
+		j.mu.Unlock()
+		return key, nil
+	}
+	if j.keys != nil && j.now().Sub(j.lastFetched) < j.minRefresh {
+		j.mu.Unlock()
+		return nil, ErrKeyNotFound
+	}
+	refresh := j.refresh
+	if refresh == nil {
+		refresh = &jwksRefresh{done: make(chan struct{})}
+		j.refresh = refresh
+		j.mu.Unlock()
+		keys, err := j.fetch(ctx)
+		j.mu.Lock()
+		if err == nil {
+			j.keys = keys
+			j.lastFetched = j.now()
+		}
+		refresh.err = err
+		j.refresh = nil
+		close(refresh.done)
+		j.mu.Unlock()
+	} else {
+		j.mu.Unlock()
+		select {
+		case <-refresh.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	if refresh.err != nil {
+		return nil, refresh.err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (j *JWKS) fetch(ctx context.Context) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, j.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := j.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to fetch key set: %v", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jwt: failed to fetch key set: status %d", res.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("jwt: failed to fetch key set: %v", err)
+	}
+	return ParseJWKS(body)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// ParseJWKS parses the signing keys of a JSON Web Key Set document.
+// Keys of unsupported types or curves, or intended for encryption, are skipped.
+func ParseJWKS(document []byte) (StaticKeys, error) {
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(document, &set); err != nil {
+		return nil, fmt.Errorf("jwt: malformed key set: %v", err)
+	}
+	keys := make(StaticKeys, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("jwt: malformed key %q: %v", jwk.Kid, err)
+		}
+		if key != nil {
+			keys[jwk.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (jwk *jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("RSA exponent is too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, nil
+		}
+		x, err := decodeBigInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
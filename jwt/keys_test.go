@@ -0,0 +1,124 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	document, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "use": "sig", "n": bigIntSegment(rsaKey.N), "e": bigIntSegment(big.NewInt(int64(rsaKey.E)))},
+			{"kty": "EC", "kid": "ec", "crv": "P-384", "x": bigIntSegment(ecKey.X), "y": bigIntSegment(ecKey.Y)},
+			{"kty": "RSA", "kid": "enc", "use": "enc", "n": bigIntSegment(rsaKey.N), "e": "AQAB"},
+			{"kty": "oct", "kid": "symmetric"},
+			{"kty": "EC", "kid": "p192", "crv": "P-192", "x": "AQ", "y": "AQ"},
+			{"kty": "OKP", "kid": "ed25519", "crv": "Ed25519", "x": "AQ"},
+		},
+	})
+	require.NoError(t, err)
+	var fetches int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		_, _ = w.Write(document)
+	}))
+	defer server.Close()
+
+	now := time.Unix(1700000000, 0)
+	jwks := NewJWKS(server.URL, WithHTTPClient(server.Client()), WithMinRefreshInterval(time.Minute))
+	jwks.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	key, err := jwks.Key(ctx, "rsa")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+	key, err = jwks.Key(ctx, "ec")
+	require.NoError(t, err)
+	assert.True(t, ecKey.PublicKey.Equal(key))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "keys are cached")
+
+	_, err = jwks.Key(ctx, "enc")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&fetches), "unknown keys are not fetched again before the refresh interval")
+
+	now = now.Add(2 * time.Minute)
+	_, err = jwks.Key(ctx, "symmetric")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches))
+}
+
+func TestJWKSFetchDoesNotBlockCachedKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	document, err := json.Marshal(map[string]interface{}{
+		"keys": []map[string]string{
+			{"kty": "RSA", "kid": "rsa", "n": bigIntSegment(rsaKey.N), "e": "AQAB"},
+		},
+	})
+	require.NoError(t, err)
+	var fetches int32
+	fetching, release := make(chan struct{}), make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&fetches, 1) > 1 {
+			close(fetching)
+			<-release
+		}
+		_, _ = w.Write(document)
+	}))
+	defer server.Close()
+
+	jwks := NewJWKS(server.URL, WithHTTPClient(server.Client()), WithMinRefreshInterval(0))
+	ctx := context.Background()
+	_, err = jwks.Key(ctx, "rsa")
+	require.NoError(t, err)
+
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := jwks.Key(ctx, "unknown")
+			results <- err
+		}()
+	}
+	<-fetching
+	key, err := jwks.Key(ctx, "rsa")
+	require.NoError(t, err)
+	assert.True(t, rsaKey.PublicKey.Equal(key))
+
+	close(release)
+	assert.ErrorIs(t, <-results, ErrKeyNotFound)
+	assert.ErrorIs(t, <-results, ErrKeyNotFound)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&fetches), "concurrent lookups share a fetch")
+}
+
+func TestJWKSFetchError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	_, err := NewJWKS(server.URL).Key(context.Background(), "kid")
+	assert.EqualError(t, err, "jwt: failed to fetch key set: status 404")
+}
+
+func TestParseJWKSMalformed(t *testing.T) {
+	_, err := ParseJWKS([]byte(`{"keys": [{"kty": "EC", "kid": "ec", "crv": "P-256", "x": "!", "y": "AQ"}]}`))
+	assert.EqualError(t, err, `jwt: malformed key "ec": illegal base64 data at input byte 0`)
+	_, err = ParseJWKS([]byte(`not json`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,257 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	_ "crypto/sha256" // register SHA-256 for crypto.Hash.New
+	_ "crypto/sha512" // register SHA-384 and SHA-512 for crypto.Hash.New
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Errors returned by Verifier.Verify. Errors from the KeySet are returned as-is.
+var (
+	ErrMalformed        = errors.New("jwt: malformed token")
+	ErrUnsupportedAlg   = errors.New("jwt: unsupported signing algorithm")
+	ErrInvalidSignature = errors.New("jwt: invalid signature")
+	ErrExpired          = errors.New("jwt: token is expired")
+	ErrNotYetValid      = errors.New("jwt: token is not valid yet")
+	ErrInvalidIssuer    = errors.New("jwt: invalid issuer")
+	ErrInvalidAudience  = errors.New("jwt: invalid audience")
+)
+
+// Claims are the claims of a verified token.
+type Claims map[string]interface{}
+
+// String returns a string claim, or "" if it is missing or not a string.
+func (c Claims) String(name string) string {
+	s, _ := c[name].(string)
+	return s
+}
+
+// Subject returns the "sub" claim.
+func (c Claims) Subject() string {
+	return c.String("sub")
+}
+
+// Scopes returns the space separated "scope" claim of an OAuth 2.0 access token.
+func (c Claims) Scopes() []string {
+	return strings.Fields(c.String("scope"))
+}
+
+// Audience returns the "aud" claim, which may be either a string or an array of strings.
+func (c Claims) Audience() []string {
+	switch aud := c["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		var out []string
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// Verifier verifies the signature and registered claims of tokens.
+type Verifier struct {
+	keys      KeySet
+	issuer    string
+	audiences []string
+	leeway    time.Duration
+	now       func() time.Time
+}
+
+// VerifierOption configures a Verifier.
+type VerifierOption func(*Verifier)
+
+// WithIssuer requires the "iss" claim to equal issuer.
+func WithIssuer(issuer string) VerifierOption {
+	return func(v *Verifier) {
+		v.issuer = issuer
+	}
+}
+
+// WithAudience requires the "aud" claim to contain one of the audiences.
+// Amazon Cognito access tokens have no "aud" claim, so the "client_id" claim is also accepted.
+func WithAudience(audiences ...string) VerifierOption {
+	return func(v *Verifier) {
+		v.audiences = append(v.audiences, audiences...)
+	}
+}
+
+// WithLeeway allows for clock skew when validating the "exp" and "nbf" claims.
+func WithLeeway(leeway time.Duration) VerifierOption {
+	return func(v *Verifier) {
+		v.leeway = leeway
+	}
+}
+
+// NewVerifier returns a Verifier for tokens signed by the keys.
+//
+// Usage:
+//
+//	verifier := jwt.NewVerifier(jwt.NewJWKS(issuer+"/.well-known/jwks.json"), jwt.WithIssuer(issuer), jwt.WithAudience(clientID))
+//	lambda.Start(func(ctx context.Context, request events.APIGatewayV2CustomAuthorizerV2Request) (events.APIGatewayV2CustomAuthorizerSimpleResponse, error) {
+//	        claims, err := verifier.Verify(ctx, request.Headers["authorization"])
+//	        ...
+//	})
+func NewVerifier(keys KeySet, options ...VerifierOption) *Verifier {
+	v := &Verifier{keys: keys, now: time.Now}
+	for _, option := range options {
+		option(v)
+	}
+	return v
+}
+
+// Verify checks the signature of the token and validates its "exp", "nbf", "iss" and "aud" claims.
+// A "Bearer " prefix, as found in an Authorization header, is ignored.
+func (v *Verifier) Verify(ctx context.Context, token string) (Claims, error) {
+	if len(token) > 7 && strings.EqualFold(token[:7], "bearer ") {
+		token = token[7:]
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrMalformed
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifySignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if err := v.validate(claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (v *Verifier) validate(claims Claims) error {
+	now := v.now()
+	exp, ok := claims["exp"].(float64)
+	if !ok {
+		return ErrExpired
+	}
+	if now.After(time.Unix(int64(exp), 0).Add(v.leeway)) {
+		return ErrExpired
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Add(v.leeway).Before(time.Unix(int64(nbf), 0)) {
+		return ErrNotYetValid
+	}
+	if v.issuer != "" && claims.String("iss") != v.issuer {
+		return ErrInvalidIssuer
+	}
+	if len(v.audiences) > 0 {
+		audiences := claims.Audience()
+		if clientID := claims.String("client_id"); clientID != "" {
+			audiences = append(audiences, clientID)
+		}
+		for _, want := range v.audiences {
+			for _, got := range audiences {
+				if want == got {
+					return nil
+				}
+			}
+		}
+		return ErrInvalidAudience
+	}
+	return nil
+}
+
+func decodeSegment(segment string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return ErrMalformed
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrMalformed
+	}
+	return nil
+}
+
+// ecdsaCurves are the curves of the keys that each ECDSA algorithm signs with.
+var ecdsaCurves = map[string]string{
+	"ES256": "P-256",
+	"ES384": "P-384",
+	"ES512": "P-521",
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signingInput string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256", "PS256":
+		hash = crypto.SHA256
+	case "RS384", "ES384", "PS384":
+		hash = crypto.SHA384
+	case "RS512", "ES512", "PS512":
+		hash = crypto.SHA512
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedAlg, alg)
+	}
+	h := hash.New()
+	h.Write([]byte(signingInput))
+	digest := h.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		switch alg[:2] {
+		case "RS":
+			if rsa.VerifyPKCS1v15(key, hash, digest, signature) != nil {
+				return ErrInvalidSignature
+			}
+			return nil
+		case "PS":
+			if rsa.VerifyPSS(key, hash, digest, signature, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}) != nil {
+				return ErrInvalidSignature
+			}
+			return nil
+		}
+	case *ecdsa.PublicKey:
+		if alg[:2] != "ES" {
+			break
+		}
+		if curve := ecdsaCurves[alg]; curve != key.Curve.Params().Name {
+			return fmt.Errorf("%w: %q with a %s key", ErrUnsupportedAlg, alg, key.Curve.Params().Name)
+		}
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if len(signature) != 2*size {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %q with key of type %T", ErrUnsupportedAlg, alg, key)
+}
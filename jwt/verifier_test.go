@@ -0,0 +1,137 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeSegment(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signToken(t *testing.T, alg, kid string, key crypto.Signer, claims map[string]interface{}) string {
+	signingInput := encodeSegment(t, map[string]string{"alg": alg, "kid": kid, "typ": "JWT"}) + "." + encodeSegment(t, claims)
+	digest := sha256.Sum256([]byte(signingInput))
+	var signature []byte
+	switch key := key.(type) {
+	case *rsa.PrivateKey:
+		var err error
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		require.NoError(t, err)
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+		require.NoError(t, err)
+		size := (key.Curve.Params().BitSize + 7) / 8
+		signature = append(r.FillBytes(make([]byte, size)), s.FillBytes(make([]byte, size))...)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func TestVerify(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ec384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	keys := StaticKeys{"rsa": &rsaKey.PublicKey, "ec": &ecKey.PublicKey, "ec384": &ec384Key.PublicKey, "other": &otherKey.PublicKey}
+	now := time.Unix(1700000000, 0)
+	valid := map[string]interface{}{
+		"sub":   "user-1",
+		"iss":   "https://issuer.example.com",
+		"aud":   []string{"api", "other"},
+		"exp":   now.Add(time.Hour).Unix(),
+		"nbf":   now.Add(-time.Hour).Unix(),
+		"scope": "read write",
+	}
+	with := func(k string, v interface{}) map[string]interface{} {
+		claims := map[string]interface{}{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if v == nil {
+			delete(claims, k)
+		} else {
+			claims[k] = v
+		}
+		return claims
+	}
+	cognitoAccessToken := with("aud", nil)
+	cognitoAccessToken["client_id"] = "client"
+
+	for name, params := range map[string]struct {
+		token     string
+		expectErr error
+	}{
+		"rsa":              {token: signToken(t, "RS256", "rsa", rsaKey, valid)},
+		"ec":               {token: signToken(t, "ES256", "ec", ecKey, valid)},
+		"bearer prefix":    {token: "Bearer " + signToken(t, "RS256", "rsa", rsaKey, valid)},
+		"cognito client":   {token: signToken(t, "RS256", "rsa", rsaKey, cognitoAccessToken)},
+		"wrong key":        {token: signToken(t, "RS256", "other", rsaKey, valid), expectErr: ErrInvalidSignature},
+		"unknown key":      {token: signToken(t, "RS256", "missing", rsaKey, valid), expectErr: ErrKeyNotFound},
+		"alg none":         {token: signToken(t, "none", "rsa", rsaKey, valid), expectErr: ErrUnsupportedAlg},
+		"alg mismatch":     {token: signToken(t, "ES256", "rsa", rsaKey, valid), expectErr: ErrUnsupportedAlg},
+		"curve mismatch":   {token: signToken(t, "ES256", "ec384", ec384Key, valid), expectErr: ErrUnsupportedAlg},
+		"expired":          {token: signToken(t, "RS256", "rsa", rsaKey, with("exp", now.Add(-time.Minute).Unix())), expectErr: ErrExpired},
+		"missing exp":      {token: signToken(t, "RS256", "rsa", rsaKey, with("exp", nil)), expectErr: ErrExpired},
+		"not yet valid":    {token: signToken(t, "RS256", "rsa", rsaKey, with("nbf", now.Add(time.Hour).Unix())), expectErr: ErrNotYetValid},
+		"wrong issuer":     {token: signToken(t, "RS256", "rsa", rsaKey, with("iss", "https://evil.example.com")), expectErr: ErrInvalidIssuer},
+		"wrong audience":   {token: signToken(t, "RS256", "rsa", rsaKey, with("aud", "someone-else")), expectErr: ErrInvalidAudience},
+		"malformed":        {token: "a.b", expectErr: ErrMalformed},
+		"malformed header": {token: "!!.b.c", expectErr: ErrMalformed},
+	} {
+		t.Run(name, func(t *testing.T) {
+			verifier := NewVerifier(keys, WithIssuer("https://issuer.example.com"), WithAudience("api", "client"))
+			verifier.now = func() time.Time { return now }
+			claims, err := verifier.Verify(context.Background(), params.token)
+			if params.expectErr != nil {
+				assert.ErrorIs(t, err, params.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "user-1", claims.Subject())
+			assert.Equal(t, []string{"read", "write"}, claims.Scopes())
+		})
+	}
+}
+
+func TestVerifyLeeway(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	token := signToken(t, "ES256", "ec", key, map[string]interface{}{"exp": now.Add(-time.Second).Unix()})
+
+	verifier := NewVerifier(StaticKeys{"ec": &key.PublicKey}, WithLeeway(time.Minute))
+	verifier.now = func() time.Time { return now }
+	_, err = verifier.Verify(context.Background(), token)
+	assert.NoError(t, err)
+}
+
+func TestClaimsAudience(t *testing.T) {
+	assert.Equal(t, []string{"a"}, Claims{"aud": "a"}.Audience())
+	assert.Equal(t, []string{"a", "b"}, Claims{"aud": []interface{}{"a", "b"}}.Audience())
+	assert.Nil(t, Claims{}.Audience())
+}
+
+func bigIntSegment(i *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(i.Bytes())
+}
@@ -44,6 +44,11 @@ import (
 // Errors from Read() (other than io.EOF) are reported as function errors.
 //
 // Note: If "TOut" is both JSON serializable and implements io.Reader, JSON serialization takes precedence.
+//
+// Start reports a handler that does not follow these rules, as checked by ValidateHandler,
+// as an error in the initialization of the function.
+//
+// To receive the raw payload without JSON decoding or copying, convert the handler to a RawHandlerFunc.
 func Start(handler interface{}) {
 	StartWithOptions(handler)
 }
@@ -100,6 +105,17 @@ func StartHandlerWithContext(ctx context.Context, handler Handler) {
 }
 
 func start(handler *handlerOptions) {
+	if handler.initError != nil {
+		// report the invalid handler as an init error, rather than failing every invoke with it
+		if api := os.Getenv(runtimeAPIStartFunction.env); api != "" {
+			if err := newRuntimeAPIClient(api).initError("Runtime.InvalidHandler", handler.initError); err != nil {
+				log.Printf("failed to report the invalid handler: %v", err)
+			}
+		}
+		logFatalf("invalid handler: %v", handler.initError)
+		return
+	}
+
 	var keys []string
 	for _, start := range startFunctions {
 		config := os.Getenv(start.env)
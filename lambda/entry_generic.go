@@ -7,6 +7,10 @@ package lambda
 
 import (
 	"context"
+	"io"
+	"reflect"
+
+	"github.com/aws/aws-lambda-go/lambda/handlertrace"
 )
 
 // HandlerFunc represents a valid input with two arguments and two returns as described by Start
@@ -16,6 +20,80 @@ type HandlerFunc[TIn, TOut any] interface {
 
 // StartHandlerFunc is the same as StartWithOptions except that it takes a generic input
 // so that the function signature can be validated at compile time.
+// The event is decoded into TIn and the response encoded from TOut without reflection.
 func StartHandlerFunc[TIn any, TOut any, H HandlerFunc[TIn, TOut]](handler H, options ...Option) {
-	start(newHandler(handler, options...))
+	start(newTypedHandler[TIn, TOut](handler, true, true, options...))
+}
+
+// StartHandlerFuncWithoutContext is the same as StartHandlerFunc for a handler that does not take a context.
+func StartHandlerFuncWithoutContext[TIn any, TOut any](handler func(TIn) (TOut, error), options ...Option) {
+	start(newTypedHandler(func(_ context.Context, event TIn) (TOut, error) {
+		return handler(event)
+	}, true, true, options...))
+}
+
+// StartHandlerFuncWithoutInput is the same as StartHandlerFunc for a handler that does not take an event.
+// The payload of the invoke is not decoded.
+func StartHandlerFuncWithoutInput[TOut any](handler func(context.Context) (TOut, error), options ...Option) {
+	start(newTypedHandler(func(ctx context.Context, _ struct{}) (TOut, error) {
+		return handler(ctx)
+	}, false, true, options...))
+}
+
+// StartHandlerFuncWithoutOutput is the same as StartHandlerFunc for a handler that only returns an error.
+// The response of a successful invoke is null.
+func StartHandlerFuncWithoutOutput[TIn any](handler func(context.Context, TIn) error, options ...Option) {
+	start(newTypedHandler(func(ctx context.Context, event TIn) (interface{}, error) {
+		return nil, handler(ctx, event)
+	}, true, false, options...))
+}
+
+// NewHandlerFunc is the same as NewHandlerWithOptions except that it takes a generic input,
+// like StartHandlerFunc, so that the Handler decodes and encodes without reflection.
+func NewHandlerFunc[TIn any, TOut any, H HandlerFunc[TIn, TOut]](handler H, options ...Option) Handler {
+	return newTypedHandler[TIn, TOut](handler, true, true, options...)
+}
+
+// newTypedHandler returns the handler options for a typed handler function.
+// hasInput and hasOutput report whether the function takes an event and returns a response,
+// so that adapted signatures behave the same as they do with Start.
+func newTypedHandler[TIn any, TOut any, H HandlerFunc[TIn, TOut]](handler H, hasInput, hasOutput bool, options ...Option) *handlerOptions {
+	h := newHandlerOptions(options...)
+	var in, out reflect.Type
+	if hasInput {
+		in = reflect.TypeOf((*TIn)(nil)).Elem()
+	}
+	if hasOutput {
+		out = reflect.TypeOf((*TOut)(nil)).Elem()
+	}
+	h.initError = validateEventTypes(in, out)
+	h.handlerFunc = func(ctx context.Context, payload []byte) (io.Reader, error) {
+		trace := handlertrace.FromContext(ctx)
+
+		var event TIn
+		if hasInput {
+			if err := h.newDecoder(payload).Decode(&event); err != nil {
+				return nil, err
+			}
+			if err := h.validateEvent(payload, &event); err != nil {
+				return nil, err
+			}
+			if nil != trace.RequestEvent {
+				trace.RequestEvent(ctx, event)
+			}
+		}
+
+		response, err := handler(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		if !hasOutput {
+			return h.encodeResponse(ctx, nil)
+		}
+		if nil != trace.ResponseEvent {
+			trace.ResponseEvent(ctx, response)
+		}
+		return h.encodeResponse(ctx, response)
+	}
+	return h
 }
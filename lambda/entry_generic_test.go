@@ -7,10 +7,12 @@ package lambda
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
 
+	"github.com/aws/aws-lambda-go/lambda/handlertrace"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -34,3 +36,81 @@ func TestStartHandlerFunc(t *testing.T) {
 	err = validateReturns(handlerType)
 	assert.NoError(t, err)
 }
+
+func TestTypedHandlerMatchesReflectHandler(t *testing.T) {
+	type event struct {
+		Name string `json:"name"`
+	}
+	greet := func(ctx context.Context, e event) (map[string]string, error) {
+		if e.Name == "" {
+			return nil, errors.New("name is required")
+		}
+		return map[string]string{"greeting": "<hello> " + e.Name}, nil
+	}
+	withoutContext := func(e event) (string, error) { return e.Name, nil }
+	withoutInput := func(ctx context.Context) (int, error) { return 42, nil }
+	withoutOutput := func(ctx context.Context, e event) error { return nil }
+
+	testCases := map[string]struct {
+		typed     *handlerOptions
+		reflected *handlerOptions
+		payloads  []string
+	}{
+		"HandlerFunc": {
+			typed:     newTypedHandler[event, map[string]string](greet, true, true, WithSetIndent("", " ")),
+			reflected: newHandler(greet, WithSetIndent("", " ")),
+			payloads:  []string{`{"name":"Lambda"}`, `{}`, `not json`},
+		},
+		"WithoutContext": {
+			typed: newTypedHandler(func(_ context.Context, e event) (string, error) {
+				return withoutContext(e)
+			}, true, true),
+			reflected: newHandler(withoutContext),
+			payloads:  []string{`{"name":"Lambda"}`},
+		},
+		"WithoutInput": {
+			typed: newTypedHandler(func(ctx context.Context, _ struct{}) (int, error) {
+				return withoutInput(ctx)
+			}, false, true),
+			reflected: newHandler(withoutInput),
+			payloads:  []string{`not json`},
+		},
+		"WithoutOutput": {
+			typed: newTypedHandler(func(ctx context.Context, e event) (interface{}, error) {
+				return nil, withoutOutput(ctx, e)
+			}, true, false),
+			reflected: newHandler(withoutOutput),
+			payloads:  []string{`{"name":"Lambda"}`},
+		},
+		"DisallowUnknownFields": {
+			typed:     newTypedHandler[event, map[string]string](greet, true, true, WithDisallowUnknownFields(true)),
+			reflected: newHandler(greet, WithDisallowUnknownFields(true)),
+			payloads:  []string{`{"name":"Lambda","age":1}`},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			for _, payload := range testCase.payloads {
+				expected, expectedErr := testCase.reflected.Invoke(context.Background(), []byte(payload))
+				actual, actualErr := testCase.typed.Invoke(context.Background(), []byte(payload))
+				assert.Equal(t, expectedErr, actualErr, payload)
+				assert.Equal(t, string(expected), string(actual), payload)
+			}
+		})
+	}
+}
+
+func TestNewHandlerFunc(t *testing.T) {
+	var requestEvent, responseEvent interface{}
+	ctx := handlertrace.NewContext(context.Background(), handlertrace.HandlerTrace{
+		RequestEvent:  func(_ context.Context, e interface{}) { requestEvent = e },
+		ResponseEvent: func(_ context.Context, e interface{}) { responseEvent = e },
+	})
+
+	handler := NewHandlerFunc(func(ctx context.Context, n int) (int, error) { return n * 2, nil })
+	response, err := handler.Invoke(ctx, []byte(`21`))
+	assert.NoError(t, err)
+	assert.Equal(t, "42", string(response))
+	assert.Equal(t, 21, requestEvent)
+	assert.Equal(t, 42, responseEvent)
+}
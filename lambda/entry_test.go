@@ -4,7 +4,12 @@ package lambda
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil" //nolint: staticcheck
 	"log"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"strings"
 	"testing"
@@ -31,3 +36,35 @@ func TestStartRuntimeAPIWithContext(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestStartInvalidHandlerReportsInitError(t *testing.T) {
+	var paths []string
+	var errorType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		errorType = r.Header.Get(trailerLambdaErrorType)
+		body, _ = ioutil.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	os.Setenv("AWS_LAMBDA_RUNTIME_API", strings.Split(server.URL, "://")[1])
+	defer os.Unsetenv("AWS_LAMBDA_RUNTIME_API")
+	var fatal string
+	logFatalf = func(format string, v ...interface{}) { fatal = fmt.Sprintf(format, v...) }
+	defer func() { logFatalf = log.Fatalf }()
+
+	Start(func(ctx context.Context, event chan string) error {
+		return nil
+	})
+
+	expectedMessage := "handler input type chan string cannot be decoded from JSON: type chan string is not supported by encoding/json"
+	assert.Equal(t, []string{"POST /2018-06-01/runtime/init/error"}, paths)
+	assert.Equal(t, "Runtime.InvalidHandler", errorType)
+	var response map[string]interface{}
+	assert.NoError(t, json.Unmarshal(body, &response))
+	assert.Equal(t, expectedMessage, response["errorMessage"])
+	assert.Equal(t, "Runtime.InvalidHandler", response["errorType"])
+	assert.Equal(t, "invalid handler: "+expectedMessage, fatal)
+}
@@ -3,6 +3,8 @@
 package lambda
 
 import (
+	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/aws/aws-lambda-go/lambda/messages"
@@ -16,10 +18,40 @@ func getErrorType(err interface{}) string {
 	return errorType.Name()
 }
 
+// lambdaError is implemented by the errors of this package that are reported with a Lambda error type,
+// rather than the name of their Go type.
+type lambdaError interface {
+	error
+	errorType() string
+}
+
+// ResponseSizeTooLargeError is returned when a response is larger than the limit set by WithMaxResponseSize.
+// It is reported with the error type Function.ResponseSizeTooLarge.
+type ResponseSizeTooLargeError struct {
+	Size  int // the size of the JSON encoded response, in bytes
+	Limit int
+}
+
+func (e *ResponseSizeTooLargeError) Error() string {
+	return fmt.Sprintf("response size of %d bytes exceeds the limit of %d bytes", e.Size, e.Limit)
+}
+
+func (e *ResponseSizeTooLargeError) errorType() string {
+	return "Function.ResponseSizeTooLarge"
+}
+
 func lambdaErrorResponse(invokeError error) *messages.InvokeResponse_Error {
 	if ive, ok := invokeError.(messages.InvokeResponse_Error); ok {
 		return &ive
 	}
+	// the error type is kept when the error is wrapped, for example by middleware adding context to it
+	var le lambdaError
+	if errors.As(invokeError, &le) {
+		return &messages.InvokeResponse_Error{
+			Message: invokeError.Error(),
+			Type:    le.errorType(),
+		}
+	}
 	var errorName string
 	if errorType := reflect.TypeOf(invokeError); errorType.Kind() == reflect.Ptr {
 		errorName = errorType.Elem().Name()
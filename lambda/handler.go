@@ -14,6 +14,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/aws/aws-lambda-go/internal/handlerhook"
 	"github.com/aws/aws-lambda-go/lambda/handlertrace"
 )
 
@@ -21,6 +22,25 @@ type Handler interface {
 	Invoke(ctx context.Context, payload []byte) ([]byte, error)
 }
 
+// RawHandlerFunc is a handler that works with the raw bytes of the invoke payload and response,
+// for functions such as image thumbnailing that are invoked directly with binary data.
+// The payload is not decoded as JSON, and is passed without being copied,
+// so it must not be retained or modified after the handler returns.
+// The response is sent as-is, and is closed after sending if it implements io.Closer.
+//
+//	lambda.Start(lambda.RawHandlerFunc(func(ctx context.Context, payload []byte) (io.Reader, error) {
+//		return bytes.NewReader(thumbnail(payload)), nil
+//	}))
+//
+// A function with the same signature that is not converted to RawHandlerFunc keeps the behavior described by Start,
+// where the payload is decoded as a JSON string.
+type RawHandlerFunc func(ctx context.Context, payload []byte) (io.Reader, error)
+
+// Invoke calls the handler and reads the response into a byte slice.
+func (f RawHandlerFunc) Invoke(ctx context.Context, payload []byte) ([]byte, error) {
+	return handlerFunc(f).Invoke(ctx, payload)
+}
+
 type handlerOptions struct {
 	handlerFunc
 	baseContext                      context.Context
@@ -33,6 +53,12 @@ type handlerOptions struct {
 	enableSIGTERM                    bool
 	sigtermCallbacks                 []func()
 	jsonOutBufferPool                *sync.Pool // contains *jsonOutBuffer
+	initError                        error      // reported by Start before polling for invokes
+	maxConcurrency                   int
+	maxResponseSize                  int
+	offloadResponse                  func(ctx context.Context, response []byte) (interface{}, error)
+	validators                       []Validator
+	admit                            func(ctx context.Context, payload []byte) (release func(), err error)
 }
 
 type Option func(*handlerOptions)
@@ -91,6 +117,43 @@ func WithEnableSIGTERM(callbacks ...func()) Option {
 	})
 }
 
+// WithMaxConcurrency limits the number of invokes handled at the same time to n,
+// when the function is configured to handle more than n concurrent invokes (AWS_LAMBDA_MAX_CONCURRENCY).
+// A value of 0 or less leaves the concurrency of the function unchanged.
+// Concurrent invokes are only handled when built with Go 1.22 or later. Older versions handle one invoke at a time,
+// whatever the concurrency of the function, so the option has no effect.
+func WithMaxConcurrency(n int) Option {
+	return Option(func(h *handlerOptions) {
+		h.maxConcurrency = n
+	})
+}
+
+// WithAdmission sets a function that is called with each invoke before it is passed to the handler,
+// so that a function handling concurrent invokes can limit the work in flight, for example by the memory it needs.
+// The admit function may block until the invoke can be handled, or until the context is done.
+// If it returns an error, the invoke fails with the error without calling the handler.
+// Otherwise the release function, if not nil, is called after the handler returns.
+func WithAdmission(admit func(ctx context.Context, payload []byte) (release func(), err error)) Option {
+	return Option(func(h *handlerOptions) {
+		h.admit = admit
+	})
+}
+
+// WithMaxResponseSize limits the size of the JSON encoded response to n bytes,
+// so that a response the Lambda service would reject, such as one over the 6MB limit of synchronous invokes,
+// fails with a ResponseSizeTooLargeError before any of it is sent.
+// Responses returned as an io.Reader are not checked.
+//
+// If onOversize is not nil, it is called with a response over the limit instead,
+// to store it elsewhere, for example in S3, and return a document pointing to it, which is sent as the response.
+// The response slice must not be retained after onOversize returns.
+func WithMaxResponseSize(n int, onOversize func(ctx context.Context, response []byte) (interface{}, error)) Option {
+	return Option(func(h *handlerOptions) {
+		h.maxResponseSize = n
+		h.offloadResponse = onOversize
+	})
+}
+
 // handlerTakesContext returns whether the handler takes a context.Context as its first argument.
 func handlerTakesContext(handler reflect.Type) (bool, error) {
 	switch handler.NumIn() {
@@ -142,6 +205,19 @@ func validateReturns(handler reflect.Type) error {
 	return nil
 }
 
+// validateSignature checks the handler function against the rules documented by Start,
+// and returns whether it takes a context.Context as its first argument.
+func validateSignature(handler reflect.Type) (bool, error) {
+	if handler.Kind() != reflect.Func {
+		return false, fmt.Errorf("handler kind %s is not %s", handler.Kind(), reflect.Func)
+	}
+	takesContext, err := handlerTakesContext(handler)
+	if err != nil {
+		return false, err
+	}
+	return takesContext, validateReturns(handler)
+}
+
 // NewHandler creates a base lambda handler from the given handler function. The
 // returned Handler performs JSON serialization and deserialization, and
 // delegates to the input handler function. The handler function parameter must
@@ -164,6 +240,14 @@ func newHandler(handlerFunc interface{}, options ...Option) *handlerOptions {
 	if h, ok := handlerFunc.(*handlerOptions); ok {
 		return h
 	}
+	h := newHandlerOptions(options...)
+	h.handlerFunc = reflectHandler(handlerFunc, h)
+	h.initError = ValidateHandler(handlerFunc)
+	return h
+}
+
+// newHandlerOptions applies the options, leaving the handlerFunc to be set by the caller.
+func newHandlerOptions(options ...Option) *handlerOptions {
 	pool := &sync.Pool{}
 	pool.New = func() interface{} {
 		return &jsonOutBuffer{pool, bytes.NewBuffer(nil)}
@@ -185,10 +269,20 @@ func newHandler(handlerFunc interface{}, options ...Option) *handlerOptions {
 	if h.enableSIGTERM {
 		enableSIGTERM(h.sigtermCallbacks)
 	}
-	h.handlerFunc = reflectHandler(handlerFunc, h)
 	return h
 }
 
+func init() {
+	// lets lambdahttp invoke handlers the way the invoke loop does, streaming their responses
+	handlerhook.Unwrap = func(handler interface{}) (context.Context, func(context.Context, []byte) (io.Reader, error), bool) {
+		h, ok := handler.(*handlerOptions)
+		if !ok {
+			return nil, nil, false
+		}
+		return h.baseContext, h.handlerFunc, true
+	}
+}
+
 type handlerFunc func(context.Context, []byte) (io.Reader, error)
 
 // back-compat for the rpc mode
@@ -241,6 +335,11 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 		return errorHandler(errors.New("handler is nil"))
 	}
 
+	// raw handlers are passed the payload buffer, and their response, without decoding or copying
+	if handler, ok := f.(RawHandlerFunc); ok {
+		return handlerFunc(handler)
+	}
+
 	// back-compat: types with reciever `Invoke(context.Context, []byte) ([]byte, error)` need the return bytes wrapped
 	if handler, ok := f.(Handler); ok {
 		return func(ctx context.Context, payload []byte) (io.Reader, error) {
@@ -254,41 +353,12 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 
 	handler := reflect.ValueOf(f)
 	handlerType := reflect.TypeOf(f)
-	if handlerType.Kind() != reflect.Func {
-		return errorHandler(fmt.Errorf("handler kind %s is not %s", handlerType.Kind(), reflect.Func))
-	}
-
-	takesContext, err := handlerTakesContext(handlerType)
+	takesContext, err := validateSignature(handlerType)
 	if err != nil {
 		return errorHandler(err)
 	}
 
-	if err := validateReturns(handlerType); err != nil {
-		return errorHandler(err)
-	}
-
-	return func(ctx context.Context, payload []byte) (outFinal io.Reader, _ error) {
-		in := bytes.NewBuffer(payload)
-		decoder := json.NewDecoder(in)
-		if h.jsonRequestUseNumber {
-			decoder.UseNumber()
-		}
-		if h.jsonRequestDisallowUnknownFields {
-			decoder.DisallowUnknownFields()
-		}
-
-		out := h.jsonOutBufferPool.Get().(*jsonOutBuffer)
-		defer func() {
-			// If the final return value is not our buffer, reset and return it to the pool.
-			// The caller of the handlerFunc does this otherwise.
-			if outFinal != out {
-				out.Close()
-			}
-		}()
-		encoder := json.NewEncoder(out)
-		encoder.SetEscapeHTML(h.jsonResponseEscapeHTML)
-		encoder.SetIndent(h.jsonResponseIndentPrefix, h.jsonResponseIndentValue)
-
+	return func(ctx context.Context, payload []byte) (io.Reader, error) {
 		trace := handlertrace.FromContext(ctx)
 
 		// construct arguments
@@ -299,7 +369,10 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 		if (handlerType.NumIn() == 1 && !takesContext) || handlerType.NumIn() == 2 {
 			eventType := handlerType.In(handlerType.NumIn() - 1)
 			event := reflect.New(eventType)
-			if err := decoder.Decode(event.Interface()); err != nil {
+			if err := h.newDecoder(payload).Decode(event.Interface()); err != nil {
+				return nil, err
+			}
+			if err := h.validateEvent(payload, event.Interface()); err != nil {
 				return nil, err
 			}
 			if nil != trace.RequestEvent {
@@ -325,27 +398,88 @@ func reflectHandler(f interface{}, h *handlerOptions) handlerFunc {
 			}
 		}
 
-		// encode to JSON
-		if err := encoder.Encode(val); err != nil {
-			// if response is not JSON serializable, but the response type is a reader, return it as-is
-			if reader, ok := val.(io.Reader); ok {
-				return reader, nil
-			}
-			return nil, err
-		}
+		return h.encodeResponse(ctx, val)
+	}
+}
+
+// newDecoder returns a JSON decoder for the payload, configured by the options.
+func (h *handlerOptions) newDecoder(payload []byte) *json.Decoder {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	if h.jsonRequestUseNumber {
+		decoder.UseNumber()
+	}
+	if h.jsonRequestDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder
+}
 
-		// if response value is an io.Reader, return it as-is
+// encodeResponse encodes the response value as JSON into a buffer from the pool,
+// unless the value is an io.Reader that is returned as-is.
+// A JSON response larger than the limit set by WithMaxResponseSize is offloaded, or fails the invoke.
+func (h *handlerOptions) encodeResponse(ctx context.Context, val interface{}) (io.Reader, error) {
+	response, err := h.encodeJSON(val)
+	if err != nil {
+		return nil, err
+	}
+	out, ok := response.(*jsonOutBuffer)
+	if !ok || h.maxResponseSize <= 0 || out.Len() <= h.maxResponseSize {
+		return response, nil
+	}
+	defer out.Close()
+	if h.offloadResponse == nil {
+		return nil, &ResponseSizeTooLargeError{Size: out.Len(), Limit: h.maxResponseSize}
+	}
+
+	pointer, err := h.offloadResponse(ctx, out.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	response, err = h.encodeJSON(pointer)
+	if err != nil {
+		return nil, err
+	}
+	if pointerOut, ok := response.(*jsonOutBuffer); ok && pointerOut.Len() > h.maxResponseSize {
+		size := pointerOut.Len()
+		pointerOut.Close()
+		return nil, &ResponseSizeTooLargeError{Size: size, Limit: h.maxResponseSize}
+	}
+	return response, nil
+}
+
+func (h *handlerOptions) encodeJSON(val interface{}) (outFinal io.Reader, _ error) {
+	out := h.jsonOutBufferPool.Get().(*jsonOutBuffer)
+	defer func() {
+		// If the final return value is not our buffer, reset and return it to the pool.
+		// The caller of the handlerFunc does this otherwise.
+		if outFinal != out {
+			out.Close()
+		}
+	}()
+	encoder := json.NewEncoder(out)
+	encoder.SetEscapeHTML(h.jsonResponseEscapeHTML)
+	encoder.SetIndent(h.jsonResponseIndentPrefix, h.jsonResponseIndentValue)
+
+	// encode to JSON
+	if err := encoder.Encode(val); err != nil {
+		// if response is not JSON serializable, but the response type is a reader, return it as-is
 		if reader, ok := val.(io.Reader); ok {
-			// back-compat, don't return the reader if the value serialized to a non-empty json
-			if strings.HasPrefix(out.String(), "{}") {
-				return reader, nil
-			}
+			return reader, nil
 		}
+		return nil, err
+	}
 
-		// back-compat, strip the encoder's trailing newline unless WithSetIndent was used
-		if h.jsonResponseIndentValue == "" && h.jsonResponseIndentPrefix == "" {
-			out.Truncate(out.Len() - 1)
+	// if response value is an io.Reader, return it as-is
+	if reader, ok := val.(io.Reader); ok {
+		// back-compat, don't return the reader if the value serialized to a non-empty json
+		if strings.HasPrefix(out.String(), "{}") {
+			return reader, nil
 		}
-		return out, nil
 	}
+
+	// back-compat, strip the encoder's trailing newline unless WithSetIndent was used
+	if h.jsonResponseIndentValue == "" && h.jsonResponseIndentPrefix == "" {
+		out.Truncate(out.Len() - 1)
+	}
+	return out, nil
 }
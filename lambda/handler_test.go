@@ -453,6 +453,33 @@ func TestInvokes(t *testing.T) {
 	}
 }
 
+func TestRawHandlerFuncDoesNotCopy(t *testing.T) {
+	payload := []byte(`"not decoded"`)
+	response := strings.NewReader("raw response")
+	var received []byte
+	raw := func(ctx context.Context, payload []byte) (io.Reader, error) {
+		received = payload
+		return response, nil
+	}
+
+	h := newHandler(RawHandlerFunc(raw))
+	out, err := h.handlerFunc(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Same(t, response, out)
+	assert.Equal(t, payload, received)
+	assert.Same(t, &payload[0], &received[0], "payload should be passed without a copy")
+
+	// without the conversion, the same signature decodes the payload as JSON
+	h = newHandler(raw)
+	_, err = h.handlerFunc(context.Background(), payload)
+	assert.Error(t, err)
+
+	response = strings.NewReader("raw response")
+	b, err := RawHandlerFunc(raw).Invoke(context.Background(), payload)
+	require.NoError(t, err)
+	assert.Equal(t, "raw response", string(b))
+}
+
 func TestInvalidJsonInput(t *testing.T) {
 	lambdaHandler := NewHandler(func(s string) error { return nil })
 	_, err := lambdaHandler.Invoke(context.TODO(), []byte(`{"invalid json`))
@@ -519,3 +546,89 @@ func TestHandlerTrace(t *testing.T) {
 		t.Error("response callbacks not called as expected", responseHistory)
 	}
 }
+
+// memoryStore stands in for a store like S3 that oversized responses are offloaded to
+type memoryStore struct {
+	objects map[string][]byte
+}
+
+func (s *memoryStore) offload(ctx context.Context, response []byte) (interface{}, error) {
+	key := fmt.Sprintf("response-%d", len(s.objects))
+	s.objects[key] = append([]byte(nil), response...)
+	return map[string]string{"location": "memory://" + key}, nil
+}
+
+func TestWithMaxResponseSize(t *testing.T) {
+	large := strings.Repeat("a", 100)
+	handler := func(size int) func() (string, error) {
+		return func() (string, error) {
+			return large[:size], nil
+		}
+	}
+
+	t.Run("response within the limit", func(t *testing.T) {
+		h := NewHandlerWithOptions(handler(10), WithMaxResponseSize(32, nil))
+		response, err := h.Invoke(context.Background(), []byte("{}"))
+		require.NoError(t, err)
+		assert.Equal(t, `"aaaaaaaaaa"`, string(response))
+	})
+
+	t.Run("response over the limit", func(t *testing.T) {
+		h := NewHandlerWithOptions(handler(50), WithMaxResponseSize(32, nil))
+		_, err := h.Invoke(context.Background(), []byte("{}"))
+		var tooLarge *ResponseSizeTooLargeError
+		require.True(t, errors.As(err, &tooLarge))
+		assert.Equal(t, 52, tooLarge.Size)
+		assert.Equal(t, 32, tooLarge.Limit)
+		assert.Equal(t, &messages.InvokeResponse_Error{
+			Message: "response size of 52 bytes exceeds the limit of 32 bytes",
+			Type:    "Function.ResponseSizeTooLarge",
+		}, lambdaErrorResponse(err))
+		assert.Equal(t, &messages.InvokeResponse_Error{
+			Message: "middleware: response size of 52 bytes exceeds the limit of 32 bytes",
+			Type:    "Function.ResponseSizeTooLarge",
+		}, lambdaErrorResponse(fmt.Errorf("middleware: %w", err)))
+	})
+
+	t.Run("typed handler response over the limit", func(t *testing.T) {
+		h := NewHandlerFunc(func(ctx context.Context, _ struct{}) (string, error) {
+			return large, nil
+		}, WithMaxResponseSize(32, nil))
+		_, err := h.Invoke(context.Background(), []byte("{}"))
+		assert.EqualError(t, err, "response size of 102 bytes exceeds the limit of 32 bytes")
+	})
+
+	t.Run("response over the limit is offloaded", func(t *testing.T) {
+		store := &memoryStore{objects: map[string][]byte{}}
+		h := NewHandlerWithOptions(handler(50), WithMaxResponseSize(40, store.offload))
+		response, err := h.Invoke(context.Background(), []byte("{}"))
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"location":"memory://response-0"}`, string(response))
+		assert.Equal(t, `"`+large[:50]+`"`, string(store.objects["response-0"]))
+	})
+
+	t.Run("offloaded pointer over the limit", func(t *testing.T) {
+		h := NewHandlerWithOptions(handler(50), WithMaxResponseSize(8, func(ctx context.Context, response []byte) (interface{}, error) {
+			return "memory://response-0", nil
+		}))
+		_, err := h.Invoke(context.Background(), []byte("{}"))
+		assert.EqualError(t, err, "response size of 21 bytes exceeds the limit of 8 bytes")
+	})
+
+	t.Run("offload error", func(t *testing.T) {
+		h := NewHandlerWithOptions(handler(50), WithMaxResponseSize(8, func(ctx context.Context, response []byte) (interface{}, error) {
+			return nil, errors.New("store unavailable")
+		}))
+		_, err := h.Invoke(context.Background(), []byte("{}"))
+		assert.EqualError(t, err, "store unavailable")
+	})
+
+	t.Run("reader responses are not checked", func(t *testing.T) {
+		h := NewHandlerWithOptions(func() (io.Reader, error) {
+			return strings.NewReader(large), nil
+		}, WithMaxResponseSize(8, nil))
+		response, err := h.Invoke(context.Background(), []byte("{}"))
+		require.NoError(t, err)
+		assert.Equal(t, large, string(response))
+	})
+}
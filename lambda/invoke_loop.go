@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -13,6 +14,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/aws/aws-lambda-go/internal/workerid"
 	"github.com/aws/aws-lambda-go/lambda/messages"
 	"github.com/aws/aws-lambda-go/lambdacontext"
 )
@@ -27,26 +29,30 @@ func unixMS(ms int64) time.Time {
 	return time.Unix(ms/msPerS, (ms%msPerS)*nsPerMS)
 }
 
+// errShouldExit is returned by handleInvoke when the handler panics, after the failure is reported.
+var errShouldExit = errors.New("calling the handler function resulted in a panic, the process should exit")
+
 func doRuntimeAPILoop(ctx context.Context, client *runtimeAPIClient, handler *handlerOptions) error {
 	for {
 		invoke, err := client.next(ctx)
 		if err != nil {
 			return err
 		}
-		if err := handleInvoke(invoke, handler); err != nil {
+		if err := handleInvoke(invoke, handler, 0, 1); err != nil {
 			return err
 		}
 	}
 }
 
-// handleInvoke returns an error if the function panics, or some other non-recoverable error occurred
-func handleInvoke(invoke *invoke, handler *handlerOptions) error {
+// handleInvoke returns an error if the function panics, or some other non-recoverable error occurred.
+// The worker is the index of the invoke loop that received the invoke, out of the number of workers.
+func handleInvoke(invoke *invoke, handler *handlerOptions, worker, workers int) error {
 	// set the deadline
 	deadline, err := parseDeadline(invoke)
 	if err != nil {
 		return reportFailure(invoke, lambdaErrorResponse(err))
 	}
-	ctx, cancel := context.WithDeadline(handler.baseContext, deadline)
+	ctx, cancel := context.WithDeadline(workerid.NewContext(handler.baseContext, worker), deadline)
 	defer cancel()
 
 	// set the invoke metadata values
@@ -63,22 +69,34 @@ func handleInvoke(invoke *invoke, handler *handlerOptions) error {
 	}
 	ctx = lambdacontext.NewContext(ctx, &lc)
 
-	// set the trace id
+	// scope the values of the invoke, released after the response is sent
+	ctx, scope := lambdacontext.NewInvokeContext(ctx)
+	defer scope.End()
+
+	// set the trace id, in the environment only if no other invoke is in flight
 	traceID := invoke.headers.Get(headerTraceID)
-	if lambdacontext.MaxConcurrency() == 1 {
+	if workers == 1 {
 		os.Setenv("_X_AMZN_TRACE_ID", traceID)
 	}
 	// nolint:staticcheck
 	ctx = context.WithValue(ctx, "x-amzn-trace-id", traceID)
 
-	// call the handler, marshal any returned error
-	response, invokeErr := callBytesHandlerFunc(ctx, invoke.payload.Bytes(), handler.handlerFunc)
+	// wait for the invoke to be admitted, if the handler limits the invokes in flight,
+	// then call the handler, marshal any returned error
+	release, invokeErr := callAdmitFunc(ctx, invoke.payload.Bytes(), handler.admit)
+	if release != nil {
+		defer release()
+	}
+	var response io.Reader
+	if invokeErr == nil {
+		response, invokeErr = callBytesHandlerFunc(ctx, invoke.payload.Bytes(), handler.handlerFunc)
+	}
 	if invokeErr != nil {
 		if err := reportFailure(invoke, invokeErr); err != nil {
 			return err
 		}
 		if invokeErr.ShouldExit {
-			return fmt.Errorf("calling the handler function resulted in a panic, the process should exit")
+			return errShouldExit
 		}
 		return nil
 	}
@@ -116,6 +134,23 @@ func reportFailure(invoke *invoke, invokeErr *messages.InvokeResponse_Error) err
 	return nil
 }
 
+// callAdmitFunc calls the admission function of the handler, if any, recovering a panic like callBytesHandlerFunc.
+func callAdmitFunc(ctx context.Context, payload []byte, admit func(context.Context, []byte) (func(), error)) (release func(), invokeErr *messages.InvokeResponse_Error) {
+	if admit == nil {
+		return nil, nil
+	}
+	defer func() {
+		if err := recover(); err != nil {
+			invokeErr = lambdaPanicResponse(err)
+		}
+	}()
+	release, err := admit(ctx, payload)
+	if err != nil {
+		return nil, lambdaErrorResponse(err)
+	}
+	return release, nil
+}
+
 func callBytesHandlerFunc(ctx context.Context, payload []byte, handler handlerFunc) (response io.Reader, invokeErr *messages.InvokeResponse_Error) {
 	defer func() {
 		if err := recover(); err != nil {
@@ -8,13 +8,23 @@ package lambda
 import (
 	"context"
 	"errors"
+	"log"
 	"sync"
 
 	"github.com/aws/aws-lambda-go/lambdacontext"
 )
 
 func startRuntimeAPILoop(api string, handler Handler) error {
-	return startRuntimeAPILoopWithConcurrency(api, handler, lambdacontext.MaxConcurrency())
+	h := newHandler(handler)
+	return startRuntimeAPILoopWithConcurrency(api, h, workerCount(h, lambdacontext.MaxConcurrency()))
+}
+
+// workerCount returns the number of invoke loops to run, limiting the concurrency of the function to WithMaxConcurrency.
+func workerCount(h *handlerOptions, concurrency int) int {
+	if h.maxConcurrency > 0 && h.maxConcurrency < concurrency {
+		return h.maxConcurrency
+	}
+	return concurrency
 }
 
 func startRuntimeAPILoopWithConcurrency(api string, handler Handler, concurrency int) error {
@@ -29,9 +39,9 @@ func startRuntimeAPILoopWithConcurrency(api string, handler Handler, concurrency
 
 	wg := &sync.WaitGroup{}
 	wg.Add(concurrency)
-	for range concurrency {
+	for worker := range concurrency {
 		go func() {
-			cancel(doRuntimeAPILoop(ctx, client, h))
+			cancel(doRuntimeAPIWorker(ctx, client, h, worker, concurrency))
 			wg.Done()
 		}()
 	}
@@ -39,3 +49,21 @@ func startRuntimeAPILoopWithConcurrency(api string, handler Handler, concurrency
 
 	return context.Cause(ctx)
 }
+
+// doRuntimeAPIWorker is the invoke loop of one of the concurrent workers.
+// An error with a single invoke is logged, so that it does not stop the other workers with their invokes in flight.
+// The worker only stops when the Runtime API can not be reached, or when the handler panics and the process should exit.
+func doRuntimeAPIWorker(ctx context.Context, client *runtimeAPIClient, handler *handlerOptions, worker, workers int) error {
+	for {
+		invoke, err := client.next(ctx)
+		if err != nil {
+			return err
+		}
+		if err := handleInvoke(invoke, handler, worker, workers); err != nil {
+			if errors.Is(err, errShouldExit) {
+				return err
+			}
+			log.Printf("worker %d: %v", worker, err)
+		}
+	}
+}
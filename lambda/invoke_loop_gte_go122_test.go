@@ -15,6 +15,7 @@ import (
 	"math/rand"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -140,6 +141,135 @@ func TestRuntimeAPILoopWithConcurrencyPanic(t *testing.T) {
 	assert.Greater(t, idx3, idx2)
 }
 
+func TestRuntimeAPILoopWithConcurrencyWorkerID(t *testing.T) {
+	nInvokes := 20
+	concurrency := 4
+
+	ts, _ := runtimeAPIServer(``, nInvokes)
+	defer ts.Close()
+
+	workers := sync.Map{}
+	handler := NewHandler(func(ctx context.Context) error {
+		worker, ok := lambdacontext.WorkerID(ctx)
+		assert.True(t, ok)
+		workers.Store(worker, true)
+		time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+		return nil
+	})
+	endpoint := strings.Split(ts.URL, "://")[1]
+	require.Error(t, startRuntimeAPILoopWithConcurrency(endpoint, handler, concurrency))
+	workers.Range(func(worker, _ interface{}) bool {
+		assert.GreaterOrEqual(t, worker.(int), 0)
+		assert.Less(t, worker.(int), concurrency)
+		return true
+	})
+}
+
+func TestRuntimeAPILoopWithConcurrencyIsolatesInvokeErrors(t *testing.T) {
+	nInvokes := 10
+	concurrency := 3
+
+	var lock sync.Mutex
+	nGets, nPosts := 0, 0
+	allPosted := make(chan struct{})
+	var responses []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			lock.Lock()
+			if nGets == nInvokes {
+				lock.Unlock()
+				<-allPosted // wait for the invokes in flight to finish
+				w.WriteHeader(http.StatusGone)
+				return
+			}
+			w.Header().Set(headerAWSRequestID, fmt.Sprintf("request-%d", nGets))
+			w.Header().Set(headerDeadlineMS, fmt.Sprint(time.Now().Add(time.Minute).UnixMilli()))
+			nGets++
+			lock.Unlock()
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		lock.Lock()
+		defer lock.Unlock()
+		if nPosts++; nPosts == nInvokes {
+			close(allPosted)
+		}
+		// the Runtime API rejects the response of a single invoke
+		if strings.Contains(r.URL.Path, "/request-3/") {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		responses = append(responses, string(body))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer ts.Close()
+
+	var logBuf bytes.Buffer
+	log.SetOutput(&logBuf)
+	defer log.SetOutput(os.Stderr)
+
+	handler := NewHandler(func(ctx context.Context) (string, error) {
+		time.Sleep(10 * time.Millisecond)
+		return "ok", nil
+	})
+	endpoint := strings.Split(ts.URL, "://")[1]
+	expectedError := fmt.Sprintf("failed to GET http://%s/2018-06-01/runtime/invocation/next: got unexpected status code: 410", endpoint)
+	assert.EqualError(t, startRuntimeAPILoopWithConcurrency(endpoint, handler, concurrency), expectedError)
+	assert.Len(t, responses, nInvokes-1)
+	assert.Contains(t, logBuf.String(), "/request-3/response: got unexpected status code: 500")
+}
+
+func TestRuntimeAPILoopTraceIDEnvironment(t *testing.T) {
+	for name, testCase := range map[string]struct {
+		concurrency int
+		expected    string
+	}{
+		"single worker":      {concurrency: 1, expected: "its-xray-time"},
+		"concurrent workers": {concurrency: 2, expected: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("_X_AMZN_TRACE_ID", "")
+			ts, _ := runtimeAPIServer(``, 4)
+			defer ts.Close()
+
+			var lock sync.Mutex
+			var envTraceIDs []string
+			handler := NewHandler(func(ctx context.Context) error {
+				lock.Lock()
+				defer lock.Unlock()
+				envTraceIDs = append(envTraceIDs, os.Getenv("_X_AMZN_TRACE_ID"))
+				return nil
+			})
+			endpoint := strings.Split(ts.URL, "://")[1]
+			require.Error(t, startRuntimeAPILoopWithConcurrency(endpoint, handler, testCase.concurrency))
+			require.NotEmpty(t, envTraceIDs)
+			for _, envTraceID := range envTraceIDs {
+				assert.Equal(t, testCase.expected, envTraceID)
+			}
+		})
+	}
+}
+
+func TestWorkerCount(t *testing.T) {
+	testCases := map[string]struct {
+		maxConcurrency int
+		concurrency    int
+		expected       int
+	}{
+		"unlimited":             {maxConcurrency: 0, concurrency: 8, expected: 8},
+		"limited":               {maxConcurrency: 2, concurrency: 8, expected: 2},
+		"limit above function":  {maxConcurrency: 16, concurrency: 8, expected: 8},
+		"negative is unlimited": {maxConcurrency: -1, concurrency: 8, expected: 8},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := newHandler(func() {}, WithMaxConcurrency(testCase.maxConcurrency))
+			assert.Equal(t, testCase.expected, workerCount(h, testCase.concurrency))
+		})
+	}
+}
+
 func TestConcurrencyWithRIE(t *testing.T) {
 	containerCmd := ""
 	if _, err := exec.LookPath("finch"); err == nil {
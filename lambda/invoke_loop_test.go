@@ -62,6 +62,88 @@ func TestRuntimeAPILoop(t *testing.T) {
 	assert.Equal(t, nInvokes, record.nPosts)
 }
 
+func TestRuntimeAPILoopWithAdmission(t *testing.T) {
+	nInvokes := 4
+
+	ts, record := runtimeAPIServer(``, nInvokes)
+	defer ts.Close()
+
+	admitted, released, handled, inFlight := 0, 0, 0, 0
+	handler := NewHandlerWithOptions(func(ctx context.Context) (string, error) {
+		handled++
+		assert.Equal(t, 1, inFlight, "the handler must be called after admission and before release")
+		return "Hello!", nil
+	}, WithAdmission(func(ctx context.Context, payload []byte) (func(), error) {
+		_, ok := lambdacontext.FromContext(ctx)
+		assert.True(t, ok)
+		if admitted == 1 {
+			admitted++
+			return nil, errors.New("too busy")
+		}
+		admitted++
+		inFlight++
+		return func() { released++; inFlight-- }, nil
+	}))
+	endpoint := strings.Split(ts.URL, "://")[1]
+	expectedError := fmt.Sprintf("failed to GET http://%s/2018-06-01/runtime/invocation/next: got unexpected status code: 410", endpoint)
+	assert.EqualError(t, startRuntimeAPILoop(endpoint, handler), expectedError)
+	assert.Equal(t, nInvokes, admitted)
+	assert.Equal(t, nInvokes-1, released)
+	assert.Equal(t, nInvokes-1, handled)
+	assert.JSONEq(t, `{"errorMessage":"too busy","errorType":"errorString"}`, string(record.responses[1]))
+	assert.Equal(t, `"Hello!"`, string(record.responses[2]))
+}
+
+func TestRuntimeAPILoopAdmissionPanic(t *testing.T) {
+	ts, record := runtimeAPIServer(``, 100)
+	defer ts.Close()
+
+	handled := false
+	handler := NewHandlerWithOptions(func(ctx context.Context) (string, error) {
+		handled = true
+		return "Hello!", nil
+	}, WithAdmission(func(ctx context.Context, payload []byte) (func(), error) {
+		panic(errors.New("admission panicked"))
+	}))
+	endpoint := strings.Split(ts.URL, "://")[1]
+	assert.EqualError(t, startRuntimeAPILoop(endpoint, handler), "calling the handler function resulted in a panic, the process should exit")
+	assert.False(t, handled)
+	assert.Equal(t, 1, record.nPosts)
+	var invokeErr messages.InvokeResponse_Error
+	require.NoError(t, json.Unmarshal(record.responses[0], &invokeErr))
+	assert.Equal(t, "admission panicked", invokeErr.Message)
+	assert.NotNil(t, invokeErr.StackTrace)
+}
+
+func TestRuntimeAPILoopEndsInvokeScope(t *testing.T) {
+	nInvokes := 3
+
+	ts, record := runtimeAPIServer(``, nInvokes)
+	defer ts.Close()
+
+	type clientKey struct{}
+	var ended []string
+	handler := NewHandler(func(ctx context.Context) (string, error) {
+		inv, ok := lambdacontext.InvokeFromContext(ctx)
+		require.True(t, ok)
+		assert.Nil(t, inv.Value(clientKey{}), "values must not leak from previous invokes")
+		lc, _ := lambdacontext.FromContext(ctx)
+		client := inv.LoadOrCreate(clientKey{}, func() interface{} {
+			return "client for " + lc.AwsRequestID
+		}).(string)
+		inv.OnEnd(func() {
+			record.lock.Lock()
+			defer record.lock.Unlock()
+			assert.Len(t, record.responses, len(ended)+1, "the invoke ends after the response is sent")
+			ended = append(ended, client)
+		})
+		return client, nil
+	})
+	endpoint := strings.Split(ts.URL, "://")[1]
+	require.Error(t, startRuntimeAPILoop(endpoint, handler))
+	assert.Equal(t, []string{"client for dummyid", "client for dummyid", "client for dummyid"}, ended)
+}
+
 func TestCustomErrorMarshaling(t *testing.T) {
 	type CustomError struct{ error }
 	errors := []error{
@@ -327,6 +409,19 @@ func TestBinaryResponseDefaultContentType(t *testing.T) {
 	assert.Equal(t, contentTypeBytes, record.contentTypes[0])
 }
 
+func TestRawHandlerFunc(t *testing.T) {
+	ts, record := runtimeAPIServer("\x89PNG\r\n\x1a\n not json", 1)
+	defer ts.Close()
+
+	handler := RawHandlerFunc(func(ctx context.Context, payload []byte) (io.Reader, error) {
+		return io.MultiReader(strings.NewReader("thumbnail of "), bytes.NewReader(payload)), nil
+	})
+	endpoint := strings.Split(ts.URL, "://")[1]
+	_ = startRuntimeAPILoop(endpoint, handler)
+	assert.Equal(t, "thumbnail of \x89PNG\r\n\x1a\n not json", string(record.responses[0]))
+	assert.Equal(t, contentTypeBytes, record.contentTypes[0])
+}
+
 func TestBinaryResponseDoesNotLeakResources(t *testing.T) {
 	numResponses := 3
 	responses := make([]*readCloser, numResponses)
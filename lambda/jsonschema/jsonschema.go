@@ -0,0 +1,485 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+// Package jsonschema validates the events of a Lambda handler against a JSON Schema document.
+//
+//	validator, err := jsonschema.NewValidator(orderSchema)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	lambda.StartWithOptions(handleOrder, lambda.WithValidator(validator))
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/lambda"
+)
+
+// NewValidator returns a lambda.Validator that checks the JSON payload of the invoke against a JSON Schema document.
+// The invoke fails with a lambda.ValidationError listing the fields that do not match the schema.
+//
+// It supports the keywords used to describe the shape of events: type, properties, required, additionalProperties,
+// items, enum, const, minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf, minLength, maxLength,
+// pattern, minItems, maxItems, uniqueItems, allOf, anyOf, oneOf, not, and $ref to local definitions in
+// "definitions" or "$defs". Annotations such as title, description and format are ignored.
+// A schema that uses another validation keyword, such as a remote $ref, is rejected so that it is not silently unchecked.
+// A $ref cycle that does not go through properties or items is rejected too, since validating it would never end.
+func NewValidator(schema []byte) (lambda.Validator, error) {
+	var root jsonSchema
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	var schemas []*jsonSchema
+	if err := root.compile(&root, &schemas); err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	state := make(map[*jsonSchema]cycleState, len(schemas))
+	for _, schema := range schemas {
+		if err := schema.checkCycles(state); err != nil {
+			return nil, fmt.Errorf("invalid JSON schema: %v", err)
+		}
+	}
+	return lambda.ValidatorFunc(func(payload []byte, _ interface{}) error {
+		decoder := json.NewDecoder(bytes.NewReader(payload))
+		decoder.UseNumber()
+		var instance interface{}
+		if err := decoder.Decode(&instance); err != nil {
+			return err
+		}
+		var fields []lambda.FieldError
+		root.validate(instance, "", &fields)
+		if len(fields) > 0 {
+			return &lambda.ValidationError{Fields: fields}
+		}
+		return nil
+	}), nil
+}
+
+// unsupportedSchemaKeywords are validation keywords that NewValidator does not implement.
+var unsupportedSchemaKeywords = []string{
+	"patternProperties", "propertyNames", "dependencies", "dependentRequired", "dependentSchemas",
+	"minProperties", "maxProperties", "contains", "additionalItems", "prefixItems",
+	"if", "then", "else", "unevaluatedProperties", "unevaluatedItems",
+}
+
+type jsonSchema struct {
+	boolean *bool // for the schemas true and false
+
+	Type                 schemaTypes            `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	AdditionalProperties *jsonSchema            `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []interface{}          `json:"enum"`
+	Const                json.RawMessage        `json:"const"`
+	Minimum              *float64               `json:"minimum"`
+	Maximum              *float64               `json:"maximum"`
+	ExclusiveMinimum     *float64               `json:"exclusiveMinimum"`
+	ExclusiveMaximum     *float64               `json:"exclusiveMaximum"`
+	MultipleOf           *float64               `json:"multipleOf"`
+	MinLength            *int                   `json:"minLength"`
+	MaxLength            *int                   `json:"maxLength"`
+	Pattern              string                 `json:"pattern"`
+	MinItems             *int                   `json:"minItems"`
+	MaxItems             *int                   `json:"maxItems"`
+	UniqueItems          bool                   `json:"uniqueItems"`
+	AllOf                []*jsonSchema          `json:"allOf"`
+	AnyOf                []*jsonSchema          `json:"anyOf"`
+	OneOf                []*jsonSchema          `json:"oneOf"`
+	Not                  *jsonSchema            `json:"not"`
+	Ref                  string                 `json:"$ref"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+
+	constValue interface{}
+	pattern    *regexp.Regexp
+	ref        *jsonSchema
+}
+
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var boolean bool
+	if err := json.Unmarshal(data, &boolean); err == nil {
+		s.boolean = &boolean
+		return nil
+	}
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for _, keyword := range unsupportedSchemaKeywords {
+		if _, ok := keywords[keyword]; ok {
+			return fmt.Errorf("keyword %q is not supported", keyword)
+		}
+	}
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// compile resolves references and compiles patterns, for the schema and its subschemas,
+// and appends them to schemas.
+func (s *jsonSchema) compile(root *jsonSchema, schemas *[]*jsonSchema) error {
+	if s == nil || s.boolean != nil {
+		return nil
+	}
+	*schemas = append(*schemas, s)
+	if s.Ref != "" {
+		ref, err := root.resolve(s.Ref)
+		if err != nil {
+			return err
+		}
+		s.ref = ref
+	}
+	if s.Pattern != "" {
+		pattern, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %v", s.Pattern, err)
+		}
+		s.pattern = pattern
+	}
+	if s.Const != nil {
+		if err := json.Unmarshal(s.Const, &s.constValue); err != nil {
+			return err
+		}
+	}
+	children := []*jsonSchema{s.AdditionalProperties, s.Items, s.Not}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, defs := range []map[string]*jsonSchema{s.Properties, s.Definitions, s.Defs} {
+		for _, child := range defs {
+			children = append(children, child)
+		}
+	}
+	for _, child := range children {
+		if err := child.compile(root, schemas); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type cycleState int
+
+const (
+	cycleChecking cycleState = iota + 1
+	cycleChecked
+)
+
+// checkCycles returns an error if the schema refers back to itself through $ref and the combinators alone.
+// Those apply to the same value as the schema, so validating it would never end. A reference that goes
+// through properties or items is fine, since it applies to a smaller part of the value each time.
+func (s *jsonSchema) checkCycles(state map[*jsonSchema]cycleState) error {
+	if s == nil || s.boolean != nil || state[s] == cycleChecked {
+		return nil
+	}
+	if state[s] == cycleChecking {
+		return errors.New("$ref refers back to the schema without going through properties or items")
+	}
+	state[s] = cycleChecking
+	children := []*jsonSchema{s.ref, s.Not}
+	children = append(children, s.AllOf...)
+	children = append(children, s.AnyOf...)
+	children = append(children, s.OneOf...)
+	for _, child := range children {
+		if err := child.checkCycles(state); err != nil {
+			return err
+		}
+	}
+	state[s] = cycleChecked
+	return nil
+}
+
+func (s *jsonSchema) resolve(ref string) (*jsonSchema, error) {
+	for prefix, defs := range map[string]map[string]*jsonSchema{"#/definitions/": s.Definitions, "#/$defs/": s.Defs} {
+		if strings.HasPrefix(ref, prefix) {
+			if def, ok := defs[strings.TrimPrefix(ref, prefix)]; ok {
+				return def, nil
+			}
+		}
+	}
+	if ref == "#" {
+		return s, nil
+	}
+	return nil, fmt.Errorf("$ref %q is not supported, only references to definitions of the schema are", ref)
+}
+
+func (s *jsonSchema) validate(instance interface{}, path string, fields *[]lambda.FieldError) {
+	fail := func(format string, args ...interface{}) {
+		*fields = append(*fields, lambda.FieldError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	if s.boolean != nil {
+		if !*s.boolean {
+			fail("is not allowed")
+		}
+		return
+	}
+	if s.ref != nil {
+		s.ref.validate(instance, path, fields)
+	}
+
+	if len(s.Type) > 0 && !s.Type.matches(instance) {
+		fail("must be of type %s", s.Type)
+		return
+	}
+	if s.Enum != nil && !containsJSON(s.Enum, instance) {
+		fail("must be one of %s", formatJSONList(s.Enum))
+	}
+	if s.Const != nil && !equalJSON(s.constValue, instance) {
+		fail("must be %s", s.Const)
+	}
+
+	switch value := instance.(type) {
+	case map[string]interface{}:
+		s.validateObject(value, path, fields)
+	case []interface{}:
+		s.validateArray(value, path, fields)
+	case string:
+		length := utf8.RuneCountInString(value)
+		if s.MinLength != nil && length < *s.MinLength {
+			fail("must be at least %d characters long", *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			fail("must be at most %d characters long", *s.MaxLength)
+		}
+		if s.pattern != nil && !s.pattern.MatchString(value) {
+			fail("must match the pattern %q", s.Pattern)
+		}
+	case json.Number:
+		s.validateNumber(value, fail)
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(instance, path, fields)
+	}
+	if len(s.AnyOf) > 0 && countMatches(s.AnyOf, instance) == 0 {
+		fail("must match at least one of the schemas in anyOf")
+	}
+	if len(s.OneOf) > 0 && countMatches(s.OneOf, instance) != 1 {
+		fail("must match exactly one of the schemas in oneOf")
+	}
+	if s.Not != nil && countMatches([]*jsonSchema{s.Not}, instance) == 1 {
+		fail("must not match the schema in not")
+	}
+}
+
+func (s *jsonSchema) validateObject(object map[string]interface{}, path string, fields *[]lambda.FieldError) {
+	for _, name := range s.Required {
+		if _, ok := object[name]; !ok {
+			*fields = append(*fields, lambda.FieldError{Path: joinPath(path, name), Message: "is required"})
+		}
+	}
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := s.Properties[name]; ok {
+			property.validate(object[name], joinPath(path, name), fields)
+		} else if s.AdditionalProperties != nil {
+			if s.AdditionalProperties.boolean != nil && !*s.AdditionalProperties.boolean {
+				*fields = append(*fields, lambda.FieldError{Path: joinPath(path, name), Message: "is not an allowed property"})
+				continue
+			}
+			s.AdditionalProperties.validate(object[name], joinPath(path, name), fields)
+		}
+	}
+}
+
+func (s *jsonSchema) validateArray(array []interface{}, path string, fields *[]lambda.FieldError) {
+	if s.MinItems != nil && len(array) < *s.MinItems {
+		*fields = append(*fields, lambda.FieldError{Path: path, Message: fmt.Sprintf("must have at least %d items", *s.MinItems)})
+	}
+	if s.MaxItems != nil && len(array) > *s.MaxItems {
+		*fields = append(*fields, lambda.FieldError{Path: path, Message: fmt.Sprintf("must have at most %d items", *s.MaxItems)})
+	}
+	if s.UniqueItems {
+	unique:
+		for i := range array {
+			for j := 0; j < i; j++ {
+				if equalJSON(array[i], array[j]) {
+					*fields = append(*fields, lambda.FieldError{Path: path, Message: "must not have duplicate items"})
+					break unique
+				}
+			}
+		}
+	}
+	if s.Items != nil {
+		for i, item := range array {
+			s.Items.validate(item, fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	}
+}
+
+func (s *jsonSchema) validateNumber(number json.Number, fail func(format string, args ...interface{})) {
+	value, err := number.Float64()
+	if err != nil {
+		fail("must be a number")
+		return
+	}
+	format := func(f float64) string { return strconv.FormatFloat(f, 'g', -1, 64) }
+	if s.Minimum != nil && value < *s.Minimum {
+		fail("must be greater than or equal to %s", format(*s.Minimum))
+	}
+	if s.Maximum != nil && value > *s.Maximum {
+		fail("must be less than or equal to %s", format(*s.Maximum))
+	}
+	if s.ExclusiveMinimum != nil && value <= *s.ExclusiveMinimum {
+		fail("must be greater than %s", format(*s.ExclusiveMinimum))
+	}
+	if s.ExclusiveMaximum != nil && value >= *s.ExclusiveMaximum {
+		fail("must be less than %s", format(*s.ExclusiveMaximum))
+	}
+	if s.MultipleOf != nil && *s.MultipleOf > 0 && !isMultiple(value, *s.MultipleOf) {
+		fail("must be a multiple of %s", format(*s.MultipleOf))
+	}
+}
+
+// isMultiple reports whether value is a multiple of divisor. Decimal fractions such as 0.1 are not exact
+// in floating point, so 0.3 / 0.1 is 2.9999999999999996, and the quotient is allowed to be a little off an integer.
+func isMultiple(value, divisor float64) bool {
+	quotient := value / divisor
+	return math.Abs(quotient-math.Round(quotient)) <= 1e-9*math.Max(1, math.Abs(quotient))
+}
+
+// schemaTypes is the type keyword, which is either a single type or a list of types.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+func (t schemaTypes) String() string {
+	return strings.Join(t, " or ")
+}
+
+func (t schemaTypes) matches(instance interface{}) bool {
+	for _, name := range t {
+		switch value := instance.(type) {
+		case nil:
+			if name == "null" {
+				return true
+			}
+		case bool:
+			if name == "boolean" {
+				return true
+			}
+		case string:
+			if name == "string" {
+				return true
+			}
+		case map[string]interface{}:
+			if name == "object" {
+				return true
+			}
+		case []interface{}:
+			if name == "array" {
+				return true
+			}
+		case json.Number:
+			if name == "number" {
+				return true
+			}
+			if f, err := value.Float64(); name == "integer" && err == nil && f == math.Trunc(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func countMatches(schemas []*jsonSchema, instance interface{}) int {
+	matches := 0
+	for _, schema := range schemas {
+		var fields []lambda.FieldError
+		schema.validate(instance, "", &fields)
+		if len(fields) == 0 {
+			matches++
+		}
+	}
+	return matches
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// equalJSON compares decoded JSON values, where numbers are either json.Number or float64.
+func equalJSON(a, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number, float64:
+		x, okA := jsonFloat(a)
+		y, okB := jsonFloat(b)
+		return okA && okB && x == y
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || !equalJSON(v, w) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equalJSON(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+func jsonFloat(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func containsJSON(values []interface{}, instance interface{}) bool {
+	for _, value := range values {
+		if equalJSON(value, instance) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatJSONList(values []interface{}) string {
+	b, _ := json.Marshal(values)
+	return string(b)
+}
@@ -0,0 +1,184 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Order",
+	"type": "object",
+	"required": ["orderId", "items"],
+	"additionalProperties": false,
+	"properties": {
+		"orderId": {"type": "string", "pattern": "^ord-[0-9]+$"},
+		"status": {"enum": ["NEW", "PAID"]},
+		"note": {"type": ["string", "null"], "maxLength": 5},
+		"items": {
+			"type": "array",
+			"minItems": 1,
+			"items": {"$ref": "#/$defs/item"}
+		}
+	},
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["sku"],
+			"properties": {
+				"sku": {"type": "string", "minLength": 1},
+				"quantity": {"type": "integer", "minimum": 1, "maximum": 10},
+				"price": {"type": "number", "exclusiveMinimum": 0, "multipleOf": 0.5}
+			}
+		}
+	}
+}`
+
+func TestNewValidator(t *testing.T) {
+	validator, err := NewValidator([]byte(orderSchema))
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		payload  string
+		expected []lambda.FieldError
+	}{
+		"valid": {
+			payload: `{"orderId":"ord-1","status":"NEW","note":null,"items":[{"sku":"a","quantity":2,"price":1.5}]}`,
+		},
+		"not an object": {
+			payload:  `[]`,
+			expected: []lambda.FieldError{{Path: "", Message: "must be of type object"}},
+		},
+		"missing required properties": {
+			payload: `{}`,
+			expected: []lambda.FieldError{
+				{Path: "orderId", Message: "is required"},
+				{Path: "items", Message: "is required"},
+			},
+		},
+		"additional property": {
+			payload:  `{"orderId":"ord-1","items":[{"sku":"a"}],"extra":1}`,
+			expected: []lambda.FieldError{{Path: "extra", Message: "is not an allowed property"}},
+		},
+		"string constraints": {
+			payload: `{"orderId":"1","note":"too long","items":[{"sku":""}]}`,
+			expected: []lambda.FieldError{
+				{Path: "items[0].sku", Message: "must be at least 1 characters long"},
+				{Path: "note", Message: "must be at most 5 characters long"},
+				{Path: "orderId", Message: `must match the pattern "^ord-[0-9]+$"`},
+			},
+		},
+		"enum": {
+			payload:  `{"orderId":"ord-1","status":"LOST","items":[{"sku":"a"}]}`,
+			expected: []lambda.FieldError{{Path: "status", Message: `must be one of ["NEW","PAID"]`}},
+		},
+		"number constraints": {
+			payload: `{"orderId":"ord-1","items":[{"sku":"a","quantity":1.5,"price":0},{"sku":"b","quantity":11,"price":1.2}]}`,
+			expected: []lambda.FieldError{
+				{Path: "items[0].price", Message: "must be greater than 0"},
+				{Path: "items[0].quantity", Message: "must be of type integer"},
+				{Path: "items[1].price", Message: "must be a multiple of 0.5"},
+				{Path: "items[1].quantity", Message: "must be less than or equal to 10"},
+			},
+		},
+		"array constraints": {
+			payload:  `{"orderId":"ord-1","items":[]}`,
+			expected: []lambda.FieldError{{Path: "items", Message: "must have at least 1 items"}},
+		},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := validator.Validate([]byte(testCase.payload), nil)
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var validationErr *lambda.ValidationError
+			require.True(t, errors.As(err, &validationErr), "got %v", err)
+			assert.Equal(t, testCase.expected, validationErr.Fields)
+		})
+	}
+}
+
+func TestValidatorCombinators(t *testing.T) {
+	validator, err := NewValidator([]byte(`{
+		"properties": {
+			"id": {"anyOf": [{"type": "string"}, {"type": "integer"}]},
+			"kind": {"oneOf": [{"const": "a"}, {"const": "b"}, {"type": "string", "maxLength": 1}]},
+			"name": {"allOf": [{"minLength": 2}, {"maxLength": 4}], "not": {"const": "root"}},
+			"tags": {"type": "array", "uniqueItems": true}
+		}
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.Validate([]byte(`{"id":1,"kind":"c","name":"abc","tags":[1,2]}`), nil))
+
+	err = validator.Validate([]byte(`{"id":1.5,"kind":"a","name":"root","tags":[1,1.0]}`), nil)
+	var validationErr *lambda.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []lambda.FieldError{
+		{Path: "id", Message: "must match at least one of the schemas in anyOf"},
+		{Path: "kind", Message: "must match exactly one of the schemas in oneOf"},
+		{Path: "name", Message: "must not match the schema in not"},
+		{Path: "tags", Message: "must not have duplicate items"},
+	}, validationErr.Fields)
+}
+
+func TestValidatorDecimalMultipleOf(t *testing.T) {
+	validator, err := NewValidator([]byte(`{"multipleOf":0.1}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.Validate([]byte(`0.3`), nil))
+	assert.NoError(t, validator.Validate([]byte(`19.9`), nil))
+	err = validator.Validate([]byte(`0.35`), nil)
+	var validationErr *lambda.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []lambda.FieldError{{Path: "", Message: "must be a multiple of 0.1"}}, validationErr.Fields)
+}
+
+func TestValidatorIgnoresFormat(t *testing.T) {
+	validator, err := NewValidator([]byte(`{"properties":{"email":{"type":"string","format":"email"}}}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.Validate([]byte(`{"email":"not an email"}`), nil))
+}
+
+func TestValidatorRecursiveSchema(t *testing.T) {
+	validator, err := NewValidator([]byte(`{
+		"$defs": {"node": {"type": "object", "required": ["name"], "properties": {"children": {"type": "array", "items": {"$ref": "#/$defs/node"}}}}},
+		"$ref": "#/$defs/node"
+	}`))
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.Validate([]byte(`{"name":"root","children":[{"name":"leaf"}]}`), nil))
+	err = validator.Validate([]byte(`{"name":"root","children":[{}]}`), nil)
+	var validationErr *lambda.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []lambda.FieldError{{Path: "children[0].name", Message: "is required"}}, validationErr.Fields)
+}
+
+func TestValidatorInvalidSchemas(t *testing.T) {
+	testCases := map[string]struct {
+		schema   string
+		expected string
+	}{
+		"malformed":           {schema: `{`, expected: "invalid JSON schema: unexpected end of JSON input"},
+		"unsupported keyword": {schema: `{"properties":{"a":{"minProperties":1}}}`, expected: `invalid JSON schema: keyword "minProperties" is not supported`},
+		"ref to itself":       {schema: `{"$ref":"#"}`, expected: "invalid JSON schema: $ref refers back to the schema without going through properties or items"},
+		"definition cycle":    {schema: `{"$defs":{"a":{"allOf":[{"$ref":"#/$defs/b"}]},"b":{"not":{"$ref":"#/$defs/a"}}},"properties":{"x":{"$ref":"#/$defs/a"}}}`, expected: "invalid JSON schema: $ref refers back to the schema without going through properties or items"},
+		"remote ref":          {schema: `{"$ref":"https://example.com/schema.json"}`, expected: `invalid JSON schema: $ref "https://example.com/schema.json" is not supported, only references to definitions of the schema are`},
+		"invalid pattern":     {schema: `{"pattern":"("}`, expected: "invalid JSON schema: invalid pattern \"(\": error parsing regexp: missing closing ): `(`"},
+	}
+	for name, testCase := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := NewValidator([]byte(testCase.schema))
+			assert.EqualError(t, err, testCase.expected)
+		})
+	}
+}
@@ -92,6 +92,8 @@ func (fn *Function) Invoke(req *messages.InvokeRequest, response *messages.Invok
 		}
 	}
 	invokeContext = lambdacontext.NewContext(invokeContext, lc)
+	invokeContext, scope := lambdacontext.NewInvokeContext(invokeContext)
+	defer scope.End()
 
 	// nolint:staticcheck
 	invokeContext = context.WithValue(invokeContext, "x-amzn-trace-id", req.XAmznTraceId)
@@ -14,7 +14,10 @@ import (
 	"log"
 	"net/http"
 	"runtime"
+	"strings"
 	"sync"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
 )
 
 const (
@@ -126,6 +129,24 @@ func (c *runtimeAPIClient) next(ctx context.Context) (*invoke, error) {
 	}, nil
 }
 
+// initError reports an error that prevents the function from handling invokes.
+// The Runtime API fails the initialization of the function, and the process should exit.
+func (c *runtimeAPIClient) initError(errorType string, err error) error {
+	url := strings.TrimSuffix(c.baseURL, "invocation/") + "init/error"
+	body := safeMarshal(&messages.InvokeResponse_Error{
+		Message: err.Error(),
+		Type:    errorType,
+	})
+	req, reqErr := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if reqErr != nil {
+		return fmt.Errorf("failed to construct POST request to %s: %v", url, reqErr)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Content-Type", contentTypeJSON)
+	req.Header.Set(trailerLambdaErrorType, errorType)
+	return c.do(req, url)
+}
+
 func (c *runtimeAPIClient) post(url string, body io.Reader, contentType string, xrayErrorCause []byte) error {
 	b := newErrorCapturingReader(body)
 	req, err := http.NewRequest(http.MethodPost, url, b)
@@ -140,6 +161,10 @@ func (c *runtimeAPIClient) post(url string, body io.Reader, contentType string,
 		req.Header.Set(headerXRayErrorCause, string(xrayErrorCause))
 	}
 
+	return c.do(req, url)
+}
+
+func (c *runtimeAPIClient) do(req *http.Request, url string) error {
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to POST to %s: %v", url, err)
@@ -0,0 +1,158 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package lambda
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+var (
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	readerType          = reflect.TypeOf((*io.Reader)(nil)).Elem()
+)
+
+// ValidateHandler reports whether the handler can be passed to Start.
+// A function must satisfy the rules documented by Start, and its "TIn" and "TOut" types
+// must be supported by the "encoding/json" standard library: "TOut" may not contain channels or functions,
+// and "TIn" may not be one. Neither may contain structs with only unexported fields, which would always
+// be decoded or encoded as empty. Fields of "TIn" that are channels or functions are allowed, because
+// decoding only fails for events that set them.
+//
+// Start calls ValidateHandler before polling for invokes, so that an invalid handler
+// fails the initialization of the function rather than every invoke.
+func ValidateHandler(handler interface{}) error {
+	if h, ok := handler.(*handlerOptions); ok {
+		return h.initError
+	}
+	if handler == nil {
+		return errors.New("handler is nil")
+	}
+	if _, ok := handler.(RawHandlerFunc); ok {
+		return nil
+	}
+	if _, ok := handler.(Handler); ok {
+		return nil
+	}
+
+	handlerType := reflect.TypeOf(handler)
+	takesContext, err := validateSignature(handlerType)
+	if err != nil {
+		return err
+	}
+	var in, out reflect.Type
+	if (handlerType.NumIn() == 1 && !takesContext) || handlerType.NumIn() == 2 {
+		in = handlerType.In(handlerType.NumIn() - 1)
+	}
+	if handlerType.NumOut() == 2 {
+		out = handlerType.Out(0)
+	}
+	return validateEventTypes(in, out)
+}
+
+// validateEventTypes checks that the event type in can be decoded from JSON,
+// and that the response type out can be encoded to JSON. A nil type is not checked.
+func validateEventTypes(in, out reflect.Type) error {
+	if in != nil {
+		if err := validateJSONType(in, true, false, map[reflect.Type]bool{}); err != nil {
+			return fmt.Errorf("handler input type %s cannot be decoded from JSON: %v", in, err)
+		}
+	}
+	// responses that implement io.Reader may be returned as raw data instead
+	if out != nil && !out.Implements(readerType) {
+		if err := validateJSONType(out, false, false, map[reflect.Type]bool{}); err != nil {
+			return fmt.Errorf("handler output type %s cannot be encoded to JSON: %v", out, err)
+		}
+	}
+	return nil
+}
+
+// validateJSONType checks that t can be encoded, or decoded if decode is set.
+// nested is set for the fields and elements of the event type. Decoding only fails for them
+// when the event has a value for them, so their types are not checked, except for structs.
+func validateJSONType(t reflect.Type, decode, nested bool, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+
+	marshaler, textMarshaler := jsonMarshalerType, textMarshalerType
+	if decode {
+		marshaler, textMarshaler = jsonUnmarshalerType, textUnmarshalerType
+	}
+	if implementsEither(t, marshaler) || implementsEither(t, textMarshaler) {
+		return nil
+	}
+
+	lenient := decode && nested
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		if lenient {
+			return nil
+		}
+		return fmt.Errorf("type %s is not supported by encoding/json", t)
+	case reflect.Ptr:
+		return validateJSONType(t.Elem(), decode, nested, seen)
+	case reflect.Slice, reflect.Array:
+		return validateJSONType(t.Elem(), decode, true, seen)
+	case reflect.Map:
+		switch key := t.Key(); key.Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !lenient && !implementsEither(key, textMarshaler) {
+				return fmt.Errorf("map key type %s is not supported by encoding/json", key)
+			}
+		}
+		return validateJSONType(t.Elem(), decode, true, seen)
+	case reflect.Struct:
+		return validateJSONStruct(t, decode, seen)
+	}
+	return nil
+}
+
+func validateJSONStruct(t reflect.Type, decode bool, seen map[reflect.Type]bool) error {
+	hasExported := false
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() && !isEmbeddedStruct(field) {
+			continue
+		}
+		hasExported = true
+		if field.Tag.Get("json") == "-" {
+			continue
+		}
+		if err := validateJSONType(field.Type, decode, true, seen); err != nil {
+			return fmt.Errorf("field %s: %v", field.Name, err)
+		}
+	}
+	if !hasExported && t.NumField() > 0 {
+		return fmt.Errorf("struct %s has no exported fields", t)
+	}
+	return nil
+}
+
+// isEmbeddedStruct reports whether the field is an embedded struct, whose exported fields are promoted
+// by encoding/json even when the struct type itself is unexported.
+func isEmbeddedStruct(field reflect.StructField) bool {
+	if !field.Anonymous {
+		return false
+	}
+	t := field.Type
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct
+}
+
+func implementsEither(t, iface reflect.Type) bool {
+	return t.Implements(iface) || (t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(iface))
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package lambda
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type validEvent struct {
+	Name      string            `json:"name"`
+	Tags      map[string]string `json:"tags"`
+	Timestamp time.Time         `json:"timestamp"`
+	Next      *validEvent       `json:"next"`
+	done      chan struct{}
+	Callback  func() `json:"-"`
+}
+
+type unexportedEvent struct {
+	name string
+	size int
+}
+
+type embeddedEvent struct {
+	validEvent
+	count int
+}
+
+func TestValidateHandler(t *testing.T) {
+	testCases := map[string]struct {
+		handler  interface{}
+		expected string
+	}{
+		"nil handler": {
+			handler:  nil,
+			expected: "handler is nil",
+		},
+		"invalid signature": {
+			handler:  func() string { return "" },
+			expected: "handler returns a single value, but it does not implement error",
+		},
+		"no event": {
+			handler: func(context.Context) error { return nil },
+		},
+		"valid event and response": {
+			handler: func(context.Context, validEvent) (*validEvent, error) { return nil, nil },
+		},
+		"empty interface event": {
+			handler: func(interface{}) {},
+		},
+		"embedded struct": {
+			handler: func(embeddedEvent) {},
+		},
+		"reader response": {
+			handler: func() (io.Reader, error) { return nil, nil },
+		},
+		"handler interface": {
+			handler: NewHandler(func() {}),
+		},
+		"raw handler": {
+			handler: RawHandlerFunc(func(context.Context, []byte) (io.Reader, error) { return nil, nil }),
+		},
+		"channel event": {
+			handler:  func(chan string) {},
+			expected: "handler input type chan string cannot be decoded from JSON: type chan string is not supported by encoding/json",
+		},
+		"func response": {
+			handler:  func() (func(), error) { return nil, nil },
+			expected: "handler output type func() cannot be encoded to JSON: type func() is not supported by encoding/json",
+		},
+		"unexported only event": {
+			handler:  func(context.Context, []unexportedEvent) error { return nil },
+			expected: "handler input type []lambda.unexportedEvent cannot be decoded from JSON: struct lambda.unexportedEvent has no exported fields",
+		},
+		"nested channel response": {
+			handler: func() (map[string]struct{ C chan int }, error) { return nil, nil },
+			expected: "handler output type map[string]struct { C chan int } cannot be encoded to JSON: " +
+				"field C: type chan int is not supported by encoding/json",
+		},
+		"channel and func fields in event": {
+			handler: func(struct {
+				Name     string
+				Done     chan struct{}
+				Callback func()
+				Values   map[complex128]string
+			}) {
+			},
+		},
+		"unsupported map key": {
+			handler:  func(map[float64]string) {},
+			expected: "handler input type map[float64]string cannot be decoded from JSON: map key type float64 is not supported by encoding/json",
+		},
+		"invalid handler options": {
+			handler:  NewHandler(func(chan string) {}),
+			expected: "handler input type chan string cannot be decoded from JSON: type chan string is not supported by encoding/json",
+		},
+	}
+	for name, testCase := range testCases {
+		testCase := testCase
+		t.Run(name, func(t *testing.T) {
+			err := ValidateHandler(testCase.handler)
+			if testCase.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expected)
+			}
+		})
+	}
+}
+
+func TestValidateTypedHandler(t *testing.T) {
+	valid := NewHandlerFunc(func(context.Context, validEvent) (string, error) { return "", nil })
+	assert.NoError(t, ValidateHandler(valid))
+
+	invalid := NewHandlerFunc(func(context.Context, unexportedEvent) (string, error) { return "", nil })
+	assert.EqualError(t, ValidateHandler(invalid),
+		"handler input type lambda.unexportedEvent cannot be decoded from JSON: struct lambda.unexportedEvent has no exported fields")
+}
+
+func TestInvalidHandlerStillReportsErrorOnInvoke(t *testing.T) {
+	handler := NewHandler(func() string { return "" })
+	_, err := handler.Invoke(context.Background(), []byte("{}"))
+	assert.Equal(t, errors.New("handler returns a single value, but it does not implement error"), err)
+	assert.True(t, strings.HasPrefix(ValidateHandler(handler).Error(), "handler returns a single value"))
+}
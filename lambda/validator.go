@@ -0,0 +1,95 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package lambda
+
+import (
+	"errors"
+	"strings"
+)
+
+// Validator checks the event of an invoke before it is passed to the handler.
+type Validator interface {
+	// Validate is called with the payload of the invoke, and a pointer to the event decoded from it.
+	// An error fails the invoke with the error type InvalidRequest, and the handler is not called.
+	Validate(payload []byte, event interface{}) error
+}
+
+// ValidatorFunc is a function that implements Validator.
+type ValidatorFunc func(payload []byte, event interface{}) error
+
+// Validate calls f.
+func (f ValidatorFunc) Validate(payload []byte, event interface{}) error {
+	return f(payload, event)
+}
+
+// MethodValidator is a Validator that calls the Validate method of events that implement it,
+// with either a value or a pointer receiver:
+//
+//	func (o Order) Validate() error {
+//		if o.Quantity < 1 {
+//			return &lambda.ValidationError{Fields: []lambda.FieldError{{Path: "quantity", Message: "must be at least 1"}}}
+//		}
+//		return nil
+//	}
+var MethodValidator Validator = ValidatorFunc(func(_ []byte, event interface{}) error {
+	if v, ok := event.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+})
+
+// WithValidator adds a Validator for the events of the handler. Validators run in the order they are added,
+// after the event is decoded, and the first error fails the invoke.
+// Validators are not run for handlers that do not take an event, or for a RawHandlerFunc.
+func WithValidator(v Validator) Option {
+	return Option(func(h *handlerOptions) {
+		h.validators = append(h.validators, v)
+	})
+}
+
+// FieldError describes why a field of an event is invalid.
+type FieldError struct {
+	Path    string // the path of the field in the JSON event, such as "Records[0].body", empty for the whole event
+	Message string
+}
+
+func (e FieldError) String() string {
+	if e.Path == "" {
+		return e.Message
+	}
+	return e.Path + ": " + e.Message
+}
+
+// ValidationError is returned when a Validator rejects the event.
+// It is reported with the error type InvalidRequest.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.String()
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) errorType() string {
+	return "InvalidRequest"
+}
+
+// validateEvent runs the validators of the handler, and reports an error as a ValidationError.
+func (h *handlerOptions) validateEvent(payload []byte, event interface{}) error {
+	for _, v := range h.validators {
+		err := v.Validate(payload, event)
+		if err == nil {
+			continue
+		}
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			return validationErr
+		}
+		return &ValidationError{Fields: []FieldError{{Message: err.Error()}}}
+	}
+	return nil
+}
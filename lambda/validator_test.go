@@ -0,0 +1,101 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved
+
+package lambda
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/lambda/messages"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validatedOrder struct {
+	OrderID  string `json:"orderId"`
+	Quantity int    `json:"quantity"`
+}
+
+func (o validatedOrder) Validate() error {
+	if o.Quantity < 1 {
+		return &ValidationError{Fields: []FieldError{{Path: "quantity", Message: "must be at least 1"}}}
+	}
+	return nil
+}
+
+type pointerValidatedOrder struct {
+	OrderID string `json:"orderId"`
+}
+
+func (o *pointerValidatedOrder) Validate() error {
+	if o.OrderID == "" {
+		return errors.New("orderId is required")
+	}
+	return nil
+}
+
+func TestWithValidator(t *testing.T) {
+	called := false
+	handler := NewHandlerWithOptions(func(ctx context.Context, order validatedOrder) (string, error) {
+		called = true
+		return order.OrderID, nil
+	}, WithValidator(MethodValidator))
+
+	response, err := handler.Invoke(context.Background(), []byte(`{"orderId":"a","quantity":2}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"a"`, string(response))
+	assert.True(t, called)
+
+	called = false
+	_, err = handler.Invoke(context.Background(), []byte(`{"orderId":"a","quantity":0}`))
+	assert.False(t, called, "the handler must not be called with an invalid event")
+	assert.EqualError(t, err, "invalid request: quantity: must be at least 1")
+	assert.Equal(t, &messages.InvokeResponse_Error{
+		Message: "invalid request: quantity: must be at least 1",
+		Type:    "InvalidRequest",
+	}, lambdaErrorResponse(err))
+}
+
+func TestWithValidatorPointerReceiver(t *testing.T) {
+	handler := NewHandlerWithOptions(func(order pointerValidatedOrder) error {
+		return nil
+	}, WithValidator(MethodValidator))
+	_, err := handler.Invoke(context.Background(), []byte(`{}`))
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []FieldError{{Message: "orderId is required"}}, validationErr.Fields)
+	assert.Equal(t, "InvalidRequest", lambdaErrorResponse(err).Type)
+}
+
+func TestWithValidatorTypedHandler(t *testing.T) {
+	required := ValidatorFunc(func(payload []byte, _ interface{}) error {
+		if !bytes.Contains(payload, []byte(`"orderId"`)) {
+			return &ValidationError{Fields: []FieldError{{Path: "orderId", Message: "is required"}}}
+		}
+		return nil
+	})
+	handler := NewHandlerFunc(func(ctx context.Context, order validatedOrder) (string, error) {
+		return order.OrderID, nil
+	}, WithValidator(required), WithValidator(MethodValidator))
+
+	_, err := handler.Invoke(context.Background(), []byte(`{"quantity":1}`))
+	assert.EqualError(t, err, "invalid request: orderId: is required")
+	_, err = handler.Invoke(context.Background(), []byte(`{"orderId":"a"}`))
+	assert.EqualError(t, err, "invalid request: quantity: must be at least 1")
+	response, err := handler.Invoke(context.Background(), []byte(`{"orderId":"a","quantity":1}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"a"`, string(response))
+}
+
+func TestValidatorNotRunWithoutEvent(t *testing.T) {
+	handler := NewHandlerWithOptions(func() (string, error) {
+		return "ok", nil
+	}, WithValidator(ValidatorFunc(func(payload []byte, event interface{}) error {
+		return errors.New("should not be called")
+	})))
+	response, err := handler.Invoke(context.Background(), []byte(`{}`))
+	require.NoError(t, err)
+	assert.Equal(t, `"ok"`, string(response))
+}
@@ -14,6 +14,8 @@ import (
 	"encoding/json"
 	"os"
 	"strconv"
+
+	"github.com/aws/aws-lambda-go/internal/workerid"
 )
 
 // LogGroupName is the name of the log group that contains the log streams of the current Lambda Function
@@ -132,3 +134,10 @@ func FromContext(ctx context.Context) (*LambdaContext, bool) {
 	lc, ok := ctx.Value(contextKey).(*LambdaContext)
 	return lc, ok
 }
+
+// WorkerID returns the index of the worker handling the invoke, from 0 to MaxConcurrency() - 1.
+// Functions that handle concurrent invokes can use it to keep per-worker state without locking.
+// The second value is false when ctx is not the context of an invoke.
+func WorkerID(ctx context.Context) (int, bool) {
+	return workerid.FromContext(ctx)
+}
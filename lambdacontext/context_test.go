@@ -1,9 +1,11 @@
 package lambdacontext
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 
+	"github.com/aws/aws-lambda-go/internal/workerid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -33,3 +35,12 @@ func TestClientContextUnmarshalJSON(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestWorkerID(t *testing.T) {
+	_, ok := WorkerID(context.Background())
+	assert.False(t, ok)
+
+	worker, ok := WorkerID(workerid.NewContext(context.Background(), 3))
+	assert.True(t, ok)
+	assert.Equal(t, 3, worker)
+}
@@ -3,6 +3,7 @@ package lambdacontext_test
 import (
 	"context"
 	"log"
+	"os"
 
 	"github.com/aws/aws-lambda-go/lambda"
 	"github.com/aws/aws-lambda-go/lambdacontext"
@@ -16,3 +17,19 @@ func ExampleFromContext() {
 		return "success", nil
 	})
 }
+
+func ExampleInvokeFromContext() {
+	type loggerKey struct{}
+	lambda.Start(func(ctx context.Context) (string, error) {
+		// create a logger for the invoke the first time it is needed, and flush it when the invoke ends
+		inv, _ := lambdacontext.InvokeFromContext(ctx)
+		logger := inv.LoadOrCreate(loggerKey{}, func() interface{} {
+			lc, _ := lambdacontext.FromContext(ctx)
+			logger := log.New(os.Stderr, lc.AwsRequestID+" ", log.LstdFlags)
+			inv.OnEnd(func() { logger.Print("invoke complete") })
+			return logger
+		}).(*log.Logger)
+		logger.Print("processing request")
+		return "success", nil
+	})
+}
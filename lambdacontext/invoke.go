@@ -0,0 +1,138 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"sync"
+)
+
+// Invoke holds the values of a single invoke, such as an HTTP client tagged with the request ID or a per-invoke logger.
+// Values stored in an Invoke are not shared with other invokes, even when the function handles concurrent invokes,
+// and are released when the invoke ends, after the response is sent.
+//
+// The invoke loop of the lambda package creates the Invoke of each invoke. Libraries get it with InvokeFromContext.
+type Invoke struct {
+	mu       sync.Mutex
+	values   map[interface{}]interface{}
+	creating map[interface{}]chan struct{}
+	cleanups []func()
+	ended    bool
+}
+
+type invokeKey struct{}
+
+// NewInvokeContext returns a new Context that carries a new Invoke, and the Invoke.
+// The caller must call End when the invoke is complete.
+func NewInvokeContext(parent context.Context) (context.Context, *Invoke) {
+	inv := &Invoke{values: make(map[interface{}]interface{})}
+	return context.WithValue(parent, invokeKey{}, inv), inv
+}
+
+// InvokeFromContext returns the Invoke stored in ctx, if any.
+func InvokeFromContext(ctx context.Context) (*Invoke, bool) {
+	inv, ok := ctx.Value(invokeKey{}).(*Invoke)
+	return inv, ok
+}
+
+// Value returns the value stored for key, or nil if there is none.
+func (inv *Invoke) Value(key interface{}) interface{} {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	return inv.values[key]
+}
+
+// SetValue stores the value for key, replacing any previous value.
+func (inv *Invoke) SetValue(key, value interface{}) {
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	inv.values[key] = value
+}
+
+// LoadOrCreate returns the value stored for key. If there is none, it stores and returns the result of create.
+// It lets a library create its per-invoke state the first time it is needed:
+//
+//	client := inv.LoadOrCreate(clientKey{}, func() interface{} {
+//		lc, _ := lambdacontext.FromContext(ctx)
+//		return newClient(lc.AwsRequestID)
+//	}).(*client)
+//
+// create is called without holding the lock of the Invoke, so it may use the Invoke, for example to register
+// a cleanup with OnEnd, but it must not call LoadOrCreate for the same key. Concurrent calls for the same key
+// wait for the value that the first one creates, so create is called once, and only the cleanups of the
+// returned value are registered. A value stored with SetValue while create runs is kept instead of the created one.
+func (inv *Invoke) LoadOrCreate(key interface{}, create func() interface{}) interface{} {
+	inv.mu.Lock()
+	for {
+		if value, ok := inv.values[key]; ok {
+			inv.mu.Unlock()
+			return value
+		}
+		done, ok := inv.creating[key]
+		if !ok {
+			break
+		}
+		inv.mu.Unlock()
+		<-done
+		inv.mu.Lock()
+	}
+	done := make(chan struct{})
+	if inv.creating == nil {
+		inv.creating = make(map[interface{}]chan struct{})
+	}
+	inv.creating[key] = done
+	inv.mu.Unlock()
+
+	// if create panics, the waiting callers wake up and one of them creates the value instead
+	defer func() {
+		inv.mu.Lock()
+		delete(inv.creating, key)
+		inv.mu.Unlock()
+		close(done)
+	}()
+	created := create()
+
+	inv.mu.Lock()
+	defer inv.mu.Unlock()
+	if value, ok := inv.values[key]; ok {
+		return value
+	}
+	inv.values[key] = created
+	return created
+}
+
+// OnEnd registers a function to be called when the invoke ends.
+// Functions are called in the reverse order of their registration.
+// If the invoke has already ended, f is called immediately.
+func (inv *Invoke) OnEnd(f func()) {
+	inv.mu.Lock()
+	if inv.ended {
+		inv.mu.Unlock()
+		f()
+		return
+	}
+	inv.cleanups = append(inv.cleanups, f)
+	inv.mu.Unlock()
+}
+
+// End calls the functions registered with OnEnd and releases the values of the invoke.
+// Calling End more than once has no effect.
+func (inv *Invoke) End() {
+	inv.mu.Lock()
+	if inv.ended {
+		inv.mu.Unlock()
+		return
+	}
+	inv.ended = true
+	cleanups := inv.cleanups
+	inv.cleanups = nil
+	inv.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+
+	inv.mu.Lock()
+	inv.values = make(map[interface{}]interface{})
+	inv.mu.Unlock()
+}
@@ -0,0 +1,114 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdacontext
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testInvokeKey struct{}
+
+func TestInvoke(t *testing.T) {
+	_, ok := InvokeFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx, inv := NewInvokeContext(context.Background())
+	fromContext, ok := InvokeFromContext(ctx)
+	require.True(t, ok)
+	assert.Same(t, inv, fromContext)
+
+	assert.Nil(t, inv.Value(testInvokeKey{}))
+	inv.SetValue(testInvokeKey{}, "a")
+	assert.Equal(t, "a", inv.Value(testInvokeKey{}))
+
+	created := 0
+	create := func() interface{} {
+		created++
+		return created
+	}
+	assert.Equal(t, 1, inv.LoadOrCreate("counter", create))
+	assert.Equal(t, 1, inv.LoadOrCreate("counter", create))
+
+	var calls []string
+	inv.OnEnd(func() { calls = append(calls, "first") })
+	inv.OnEnd(func() {
+		assert.Equal(t, "a", inv.Value(testInvokeKey{}), "values are released after the cleanups")
+		calls = append(calls, "second")
+	})
+	inv.End()
+	inv.End()
+	assert.Equal(t, []string{"second", "first"}, calls)
+	assert.Nil(t, inv.Value(testInvokeKey{}))
+
+	inv.OnEnd(func() { calls = append(calls, "late") })
+	assert.Equal(t, []string{"second", "first", "late"}, calls)
+}
+
+func TestLoadOrCreateMayUseTheInvoke(t *testing.T) {
+	_, inv := NewInvokeContext(context.Background())
+	closed := false
+	value := inv.LoadOrCreate(testInvokeKey{}, func() interface{} {
+		inv.OnEnd(func() { closed = true })
+		inv.SetValue("other", "b")
+		return "a"
+	})
+	assert.Equal(t, "a", value)
+	assert.Equal(t, "b", inv.Value("other"))
+	inv.End()
+	assert.True(t, closed)
+}
+
+func TestLoadOrCreateKeepsTheFirstValue(t *testing.T) {
+	_, inv := NewInvokeContext(context.Background())
+	value := inv.LoadOrCreate(testInvokeKey{}, func() interface{} {
+		// another caller stores a value while this one is being created
+		inv.SetValue(testInvokeKey{}, "first")
+		return "second"
+	})
+	assert.Equal(t, "first", value)
+	assert.Equal(t, "first", inv.Value(testInvokeKey{}))
+}
+
+func TestLoadOrCreateConcurrentCallsCreateOnce(t *testing.T) {
+	_, inv := NewInvokeContext(context.Background())
+	var creates, cleanups int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	values := make([]interface{}, 8)
+	for i := range values {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			values[i] = inv.LoadOrCreate(testInvokeKey{}, func() interface{} {
+				atomic.AddInt32(&creates, 1)
+				inv.OnEnd(func() { atomic.AddInt32(&cleanups, 1) })
+				<-release
+				return "value"
+			})
+		}(i)
+	}
+	close(release)
+	wg.Wait()
+	inv.End()
+
+	for _, value := range values {
+		assert.Equal(t, "value", value)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&creates))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&cleanups))
+}
+
+func TestInvokesAreIsolated(t *testing.T) {
+	_, inv1 := NewInvokeContext(context.Background())
+	_, inv2 := NewInvokeContext(context.Background())
+	inv1.SetValue(testInvokeKey{}, "request-1")
+	inv2.SetValue(testInvokeKey{}, "request-2")
+	assert.Equal(t, "request-1", inv1.Value(testInvokeKey{}))
+	assert.Equal(t, "request-2", inv2.Value(testInvokeKey{}))
+}
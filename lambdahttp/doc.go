@@ -0,0 +1,11 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+// Package lambdahttp serves Lambda HTTP handlers from a local HTTP server for development.
+//
+// Incoming requests are converted into the event payload that a Lambda Function URL or Amazon API Gateway
+// would send, the handler is invoked in-process, and the response is translated back into an HTTP response.
+// This gives a real endpoint to develop against, without deploying or running a container.
+//
+// See https://docs.aws.amazon.com/lambda/latest/dg/urls-invocation.html
+// and https://docs.aws.amazon.com/apigateway/latest/developerguide/http-api-develop-integrations-lambda.html
+package lambdahttp
@@ -0,0 +1,199 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdahttp
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const localAccountID = "123456789012"
+
+func newFunctionURLRequest(r *http.Request, body []byte, requestID string) ([]byte, error) {
+	now := time.Now()
+	domainPrefix := domainPrefix(r.Host)
+	event := events.LambdaFunctionURLRequest{
+		Version:               "2.0",
+		RawPath:               r.URL.EscapedPath(),
+		RawQueryString:        r.URL.RawQuery,
+		Cookies:               cookies(r),
+		Headers:               singleValueHeaders(r),
+		QueryStringParameters: singleValueQuery(r),
+		RequestContext: events.LambdaFunctionURLRequestContext{
+			AccountID:    localAccountID,
+			RequestID:    requestID,
+			APIID:        domainPrefix,
+			DomainName:   r.Host,
+			DomainPrefix: domainPrefix,
+			Time:         now.UTC().Format(timeFormat),
+			TimeEpoch:    now.UnixNano() / int64(time.Millisecond),
+			HTTP: events.LambdaFunctionURLRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  sourceIP(r),
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+	event.Body, event.IsBase64Encoded = encodeBody(body)
+	return json.Marshal(event)
+}
+
+func newAPIGatewayV2HTTPRequest(r *http.Request, body []byte, requestID string) ([]byte, error) {
+	now := time.Now()
+	domainPrefix := domainPrefix(r.Host)
+	event := events.APIGatewayV2HTTPRequest{
+		Version:               "2.0",
+		RouteKey:              "$default",
+		RawPath:               r.URL.EscapedPath(),
+		RawQueryString:        r.URL.RawQuery,
+		Cookies:               cookies(r),
+		Headers:               singleValueHeaders(r),
+		QueryStringParameters: singleValueQuery(r),
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RouteKey:     "$default",
+			AccountID:    localAccountID,
+			Stage:        "$default",
+			RequestID:    requestID,
+			APIID:        domainPrefix,
+			DomainName:   r.Host,
+			DomainPrefix: domainPrefix,
+			Time:         now.UTC().Format(timeFormat),
+			TimeEpoch:    now.UnixNano() / int64(time.Millisecond),
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Protocol:  r.Proto,
+				SourceIP:  sourceIP(r),
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+	event.Body, event.IsBase64Encoded = encodeBody(body)
+	return json.Marshal(event)
+}
+
+func newAPIGatewayProxyRequest(r *http.Request, body []byte, requestID string) ([]byte, error) {
+	now := time.Now()
+	event := events.APIGatewayProxyRequest{
+		Resource:              "/{proxy+}",
+		Path:                  r.URL.Path,
+		HTTPMethod:            r.Method,
+		Headers:               map[string]string{},
+		MultiValueHeaders:     map[string][]string{},
+		QueryStringParameters: singleValueQuery(r),
+		PathParameters:        map[string]string{"proxy": strings.TrimPrefix(r.URL.Path, "/")},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			AccountID:        localAccountID,
+			Stage:            "local",
+			DomainName:       r.Host,
+			DomainPrefix:     domainPrefix(r.Host),
+			RequestID:        requestID,
+			Protocol:         r.Proto,
+			ResourcePath:     "/{proxy+}",
+			Path:             r.URL.Path,
+			HTTPMethod:       r.Method,
+			RequestTime:      now.UTC().Format(timeFormat),
+			RequestTimeEpoch: now.UnixNano() / int64(time.Millisecond),
+			APIID:            domainPrefix(r.Host),
+			Identity: events.APIGatewayRequestIdentity{
+				SourceIP:  sourceIP(r),
+				UserAgent: r.UserAgent(),
+			},
+		},
+	}
+	// REST APIs pass the last value of a repeated header or query parameter in the single value maps
+	for k, v := range r.Header {
+		event.Headers[k] = v[len(v)-1]
+		event.MultiValueHeaders[k] = v
+	}
+	if r.Host != "" {
+		event.Headers["Host"] = r.Host
+		event.MultiValueHeaders["Host"] = []string{r.Host}
+	}
+	if query := r.URL.Query(); len(query) > 0 {
+		event.QueryStringParameters = make(map[string]string, len(query))
+		event.MultiValueQueryStringParameters = query
+		for k, v := range query {
+			event.QueryStringParameters[k] = v[len(v)-1]
+		}
+	}
+	event.Body, event.IsBase64Encoded = encodeBody(body)
+	return json.Marshal(event)
+}
+
+// timeFormat is the format of the request time in the request context, eg: "18/Apr/2023:17:01:46 +0000"
+const timeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// encodeBody returns the body as a string, base64 encoding it unless it is valid UTF-8 text.
+func encodeBody(body []byte) (string, bool) {
+	if utf8.Valid(body) {
+		return string(body), false
+	}
+	return base64.StdEncoding.EncodeToString(body), true
+}
+
+// singleValueHeaders lowercases header names and joins repeated values with commas, as version 2.0 payloads do.
+// The Cookie header is sent separately, see cookies.
+func singleValueHeaders(r *http.Request) map[string]string {
+	headers := make(map[string]string, len(r.Header)+1)
+	for k, v := range r.Header {
+		if k == "Cookie" {
+			continue
+		}
+		headers[strings.ToLower(k)] = strings.Join(v, ",")
+	}
+	if r.Host != "" {
+		headers["host"] = r.Host
+	}
+	return headers
+}
+
+// singleValueQuery joins repeated query parameters with commas, as version 2.0 payloads do.
+func singleValueQuery(r *http.Request) map[string]string {
+	query := r.URL.Query()
+	if len(query) == 0 {
+		return nil
+	}
+	params := make(map[string]string, len(query))
+	for k, v := range query {
+		params[k] = strings.Join(v, ",")
+	}
+	return params
+}
+
+func cookies(r *http.Request) []string {
+	var cookies []string
+	for _, header := range r.Header["Cookie"] {
+		for _, cookie := range strings.Split(header, ";") {
+			if cookie = strings.TrimSpace(cookie); cookie != "" {
+				cookies = append(cookies, cookie)
+			}
+		}
+	}
+	return cookies
+}
+
+func sourceIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func domainPrefix(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	prefix, _, _ := strings.Cut(host, ".")
+	return prefix
+}
@@ -0,0 +1,158 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdahttp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+)
+
+// streamingDelimiter separates the JSON prelude from the body of a streamed HTTP integration response.
+var streamingDelimiter = []byte{0, 0, 0, 0, 0, 0, 0, 0}
+
+type response struct {
+	StatusCode        int                 `json:"statusCode"`
+	Headers           map[string]string   `json:"headers"`
+	MultiValueHeaders map[string][]string `json:"multiValueHeaders"`
+	Body              string              `json:"body"`
+	IsBase64Encoded   bool                `json:"isBase64Encoded"`
+	Cookies           []string            `json:"cookies"`
+}
+
+// The write functions only return errors that occur before the status code is written,
+// so that the caller can still respond with an error instead.
+
+// writeV2Response writes a version 2.0 payload response, used by both Function URLs and HTTP APIs.
+// A response without a status code is treated as the JSON body of a 200 response.
+// A response with the streaming prelude is written as it is read, flushing each part of the body to the client.
+func writeV2Response(w http.ResponseWriter, r io.Reader) error {
+	var payload []byte
+	// The prelude is a JSON object, which can't contain NUL bytes, so a payload that doesn't start with one
+	// or whose first delimiter doesn't follow a valid prelude is not streamed.
+	// Only the newly read bytes, and the end of the previous ones that could start a delimiter, are searched.
+	streaming, searchFrom := true, 0
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		payload = append(payload, chunk[:n]...)
+		if trimmed := bytes.TrimLeft(payload, " \t\r\n"); streaming && len(trimmed) > 0 && trimmed[0] != '{' {
+			streaming = false
+		}
+		if streaming {
+			if i := bytes.Index(payload[searchFrom:], streamingDelimiter); i >= 0 {
+				i += searchFrom
+				var res response
+				if json.Unmarshal(payload[:i], &res) != nil {
+					streaming = false
+				} else {
+					writeHeader(w, &res)
+					streamBody(w, payload[i+len(streamingDelimiter):], r, err)
+					return nil
+				}
+			} else if searchFrom = len(payload) - len(streamingDelimiter) + 1; searchFrom < 0 {
+				searchFrom = 0
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var res response
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(payload, &fields) != nil || fields["statusCode"] == nil {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(payload)
+		return nil
+	}
+	if err := json.Unmarshal(payload, &res); err != nil {
+		return err
+	}
+	return writeResponse(w, &res)
+}
+
+// streamBody writes the start of the body that was read with the prelude, and copies the rest of it,
+// flushing after each write so that the client receives the body as the handler produces it.
+// readErr is the error of the read that returned the start of the body.
+func streamBody(w http.ResponseWriter, start []byte, r io.Reader, readErr error) {
+	flusher, _ := w.(http.Flusher)
+	write := func(b []byte) bool {
+		if _, err := w.Write(b); err != nil {
+			return false
+		}
+		if flusher != nil {
+			flusher.Flush()
+		}
+		return true
+	}
+	if !write(start) || readErr != nil {
+		return
+	}
+	chunk := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 && !write(chunk[:n]) {
+			return
+		}
+		if err != nil {
+			if err != io.EOF {
+				// the status code is already sent, so the error can only end the response early
+				log.Printf("lambdahttp: failed to read the response body: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// writeAPIGatewayProxyResponse writes a version 1.0 payload response, which must include a status code.
+func writeAPIGatewayProxyResponse(w http.ResponseWriter, payload []byte) error {
+	var res response
+	if err := json.Unmarshal(payload, &res); err != nil {
+		return err
+	}
+	if res.StatusCode == 0 {
+		return errors.New("malformed Lambda proxy response: missing statusCode")
+	}
+	res.Cookies = nil
+	return writeResponse(w, &res)
+}
+
+func writeResponse(w http.ResponseWriter, res *response) error {
+	body := []byte(res.Body)
+	if res.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(res.Body)
+		if err != nil {
+			return err
+		}
+		body = decoded
+	}
+	writeHeader(w, res)
+	_, _ = w.Write(body)
+	return nil
+}
+
+func writeHeader(w http.ResponseWriter, res *response) {
+	for k, v := range res.MultiValueHeaders {
+		for _, value := range v {
+			w.Header().Add(k, value)
+		}
+	}
+	for k, v := range res.Headers {
+		w.Header().Set(k, v)
+	}
+	for _, cookie := range res.Cookies {
+		w.Header().Add("Set-Cookie", cookie)
+	}
+	if res.StatusCode == 0 {
+		res.StatusCode = http.StatusOK
+	}
+	w.WriteHeader(res.StatusCode)
+}
@@ -0,0 +1,185 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdahttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-lambda-go/internal/handlerhook"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+)
+
+// Format selects the event payload that requests are converted into before invoking the handler.
+type Format int
+
+const (
+	// FunctionURL sends events.LambdaFunctionURLRequest payloads, and accepts events.LambdaFunctionURLResponse
+	// or events.LambdaFunctionURLStreamingResponse responses.
+	FunctionURL Format = iota
+	// APIGatewayV2HTTP sends events.APIGatewayV2HTTPRequest payloads, and accepts events.APIGatewayV2HTTPResponse responses.
+	APIGatewayV2HTTP
+	// APIGatewayProxy sends events.APIGatewayProxyRequest payloads, and accepts events.APIGatewayProxyResponse responses.
+	APIGatewayProxy
+)
+
+func (f Format) String() string {
+	switch f {
+	case FunctionURL:
+		return "FunctionURL"
+	case APIGatewayV2HTTP:
+		return "APIGatewayV2HTTP"
+	case APIGatewayProxy:
+		return "APIGatewayProxy"
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// NewHandler returns an http.Handler that invokes a Lambda handler for each request.
+// The handler and options follow the same rules as lambda.StartWithOptions.
+//
+// Responses using the streaming prelude, such as events.LambdaFunctionURLStreamingResponse, are supported,
+// and their body is flushed to the client as the handler writes it.
+//
+// Usage:
+//
+//	http.ListenAndServe(":8080", lambdahttp.NewHandler(lambdahttp.APIGatewayV2HTTP, handleRequest))
+func NewHandler(format Format, handler interface{}, options ...lambda.Option) http.Handler {
+	s := &server{
+		format:      format,
+		baseContext: context.Background(),
+	}
+	h := lambda.NewHandlerWithOptions(handler, options...)
+	if base, invoke, ok := handlerhook.Unwrap(h); ok {
+		s.baseContext, s.invoke = base, invoke
+	} else {
+		s.invoke = func(ctx context.Context, payload []byte) (io.Reader, error) {
+			response, err := h.Invoke(ctx, payload)
+			return bytes.NewReader(response), err
+		}
+	}
+	return s
+}
+
+// ListenAndServe listens on the TCP network address addr and serves requests with the Lambda handler.
+// See NewHandler for how requests and responses are converted.
+//
+// Usage:
+//
+//	func main() {
+//	        if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+//	                log.Fatal(lambdahttp.ListenAndServe("localhost:8080", lambdahttp.FunctionURL, lambdaurl.Wrap(mux)))
+//	        }
+//	        lambdaurl.Start(mux)
+//	}
+func ListenAndServe(addr string, format Format, handler interface{}, options ...lambda.Option) error {
+	return http.ListenAndServe(addr, NewHandler(format, handler, options...))
+}
+
+type server struct {
+	format      Format
+	baseContext context.Context
+	invoke      func(ctx context.Context, payload []byte) (io.Reader, error)
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	requestID := newRequestID()
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var payload []byte
+	switch s.format {
+	case FunctionURL:
+		payload, err = newFunctionURLRequest(r, body, requestID)
+	case APIGatewayV2HTTP:
+		payload, err = newAPIGatewayV2HTTPRequest(r, body, requestID)
+	case APIGatewayProxy:
+		payload, err = newAPIGatewayProxyRequest(r, body, requestID)
+	default:
+		err = fmt.Errorf("unsupported format %v", s.format)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := s.invokeContext(r, requestID)
+	defer cancel()
+	response, err := invoke(ctx, s.invoke, payload)
+	if err != nil {
+		log.Printf("lambdahttp: request %s: %v", requestID, err)
+		writeInternalServerError(w)
+		return
+	}
+	if closer, ok := response.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	if s.format == APIGatewayProxy {
+		var b []byte
+		if b, err = io.ReadAll(response); err == nil {
+			err = writeAPIGatewayProxyResponse(w, b)
+		}
+	} else {
+		err = writeV2Response(w, response)
+	}
+	if err != nil {
+		log.Printf("lambdahttp: request %s: %v", requestID, err)
+		writeInternalServerError(w)
+	}
+}
+
+// invokeTimeout is the deadline of local invokes, which is the longest timeout that a function can have.
+const invokeTimeout = 15 * time.Minute
+
+// invokeContext returns the context of an invoke, which has the values of the handler's base context
+// and a deadline, like in the Lambda invoke loop, and is canceled when the client goes away.
+func (s *server) invokeContext(r *http.Request, requestID string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithDeadline(s.baseContext, time.Now().Add(invokeTimeout))
+	go func() {
+		select {
+		case <-r.Context().Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return lambdacontext.NewContext(ctx, &lambdacontext.LambdaContext{
+		AwsRequestID:       requestID,
+		InvokedFunctionArn: "arn:aws:lambda:us-east-1:123456789012:function:local",
+	}), cancel
+}
+
+// invoke calls the handler, converting any panic into an error, as the Lambda invoke loop would.
+func invoke(ctx context.Context, handler func(context.Context, []byte) (io.Reader, error), payload []byte) (response io.Reader, err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("handler panic: %v", v)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// writeInternalServerError matches the response returned by Function URLs and API Gateway when the function fails.
+func writeInternalServerError(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadGateway)
+	_, _ = io.WriteString(w, `{"message":"Internal Server Error"}`)
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,255 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package lambdahttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil" //nolint: staticcheck
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFunctionURL(t *testing.T) {
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context, req *events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLResponse, error) {
+		lc, ok := lambdacontext.FromContext(ctx)
+		require.True(t, ok)
+		assert.Equal(t, lc.AwsRequestID, req.RequestContext.RequestID)
+		assert.Equal(t, "POST", req.RequestContext.HTTP.Method)
+		assert.Equal(t, "/hello/world", req.RawPath)
+		assert.Equal(t, "a=1&a=2&b=3", req.RawQueryString)
+		assert.Equal(t, map[string]string{"a": "1,2", "b": "3"}, req.QueryStringParameters)
+		assert.Equal(t, []string{"foo=bar", "hello=world"}, req.Cookies)
+		assert.Equal(t, "h1,h2", req.Headers["x-multi"])
+		assert.Equal(t, "127.0.0.1", req.RequestContext.HTTP.SourceIP)
+		assert.Equal(t, `{"hello":"world"}`, req.Body)
+		assert.False(t, req.IsBase64Encoded)
+		return &events.LambdaFunctionURLResponse{
+			StatusCode:      http.StatusCreated,
+			Headers:         map[string]string{"Content-Type": "text/plain"},
+			Cookies:         []string{"a=b", "c=d"},
+			Body:            "aGVsbG8=",
+			IsBase64Encoded: true,
+		}, nil
+	}))
+	defer server.Close()
+
+	req, err := http.NewRequest("POST", server.URL+"/hello/world?a=1&a=2&b=3", strings.NewReader(`{"hello":"world"}`))
+	require.NoError(t, err)
+	req.Header.Add("X-Multi", "h1")
+	req.Header.Add("X-Multi", "h2")
+	req.Header.Set("Cookie", "foo=bar; hello=world")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "text/plain", res.Header.Get("Content-Type"))
+	assert.Equal(t, []string{"a=b", "c=d"}, res.Header["Set-Cookie"])
+	assert.Equal(t, "hello", string(body))
+}
+
+func TestFunctionURLStreamingResponse(t *testing.T) {
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context, req *events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+		return &events.LambdaFunctionURLStreamingResponse{
+			StatusCode: http.StatusTeapot,
+			Headers:    map[string]string{"Content-Type": "text/html"},
+			Body:       strings.NewReader("<html></html>"),
+		}, nil
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, res.StatusCode)
+	assert.Equal(t, "text/html", res.Header.Get("Content-Type"))
+	assert.Equal(t, "<html></html>", string(body))
+}
+
+func TestAPIGatewayV2HTTPImplicitResponse(t *testing.T) {
+	server := httptest.NewServer(NewHandler(APIGatewayV2HTTP, func(req events.APIGatewayV2HTTPRequest) (map[string]string, error) {
+		assert.Equal(t, "$default", req.RouteKey)
+		assert.True(t, req.IsBase64Encoded)
+		assert.Equal(t, "/wA=", req.Body)
+		return map[string]string{"hello": "world"}, nil
+	}))
+	defer server.Close()
+
+	res, err := http.Post(server.URL, "application/octet-stream", strings.NewReader("\xff\x00"))
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.JSONEq(t, `{"hello":"world"}`, string(body))
+}
+
+func TestAPIGatewayProxy(t *testing.T) {
+	server := httptest.NewServer(NewHandler(APIGatewayProxy, func(req events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		assert.Equal(t, "GET", req.HTTPMethod)
+		assert.Equal(t, "/items/1", req.Path)
+		assert.Equal(t, "items/1", req.PathParameters["proxy"])
+		assert.Equal(t, []string{"1", "2"}, req.MultiValueQueryStringParameters["a"])
+		assert.Equal(t, "2", req.QueryStringParameters["a"])
+		return events.APIGatewayProxyResponse{
+			StatusCode:        http.StatusOK,
+			MultiValueHeaders: map[string][]string{"X-Values": {"1", "2"}},
+			Body:              "ok",
+		}, nil
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "/items/1?a=1&a=2")
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, []string{"1", "2"}, res.Header["X-Values"])
+	assert.Equal(t, "ok", string(body))
+}
+
+func TestHandlerFailures(t *testing.T) {
+	for name, params := range map[string]struct {
+		format  Format
+		handler interface{}
+	}{
+		"error": {
+			format:  FunctionURL,
+			handler: func() error { return errors.New("barf") },
+		},
+		"panic": {
+			format:  FunctionURL,
+			handler: func() error { panic("barf") },
+		},
+		"proxy response without status code": {
+			format:  APIGatewayProxy,
+			handler: func() (string, error) { return "hello", nil },
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(NewHandler(params.format, params.handler))
+			defer server.Close()
+
+			res, err := http.Get(server.URL)
+			require.NoError(t, err)
+			defer res.Body.Close()
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err)
+			assert.Equal(t, http.StatusBadGateway, res.StatusCode)
+			assert.Equal(t, `{"message":"Internal Server Error"}`, string(body))
+		})
+	}
+}
+
+type contextKey struct{}
+
+func TestOptionsAreApplied(t *testing.T) {
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context) (string, error) {
+		return ctx.Value(contextKey{}).(string), nil
+	}, lambda.WithContextValue(contextKey{}, "hello")))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `"hello"`, string(body))
+}
+
+func TestFunctionURLStreamingResponseIsFlushed(t *testing.T) {
+	body, bodyWriter := io.Pipe()
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context, req *events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
+		return &events.LambdaFunctionURLStreamingResponse{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "text/plain"},
+			Body:       body,
+		}, nil
+	}))
+	defer server.Close()
+
+	go func() {
+		_, _ = io.WriteString(bodyWriter, "first")
+	}()
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	// the first part of the body arrives while the handler is still writing
+	first := make([]byte, len("first"))
+	_, err = io.ReadFull(res.Body, first)
+	require.NoError(t, err)
+	assert.Equal(t, "first", string(first))
+
+	_, _ = io.WriteString(bodyWriter, " second")
+	bodyWriter.Close()
+	rest, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, " second", string(rest))
+}
+
+func TestBaseContextIsApplied(t *testing.T) {
+	ctx := context.WithValue(context.Background(), contextKey{}, "base")
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context) (string, error) {
+		lc, ok := lambdacontext.FromContext(ctx)
+		require.True(t, ok)
+		assert.NotEmpty(t, lc.AwsRequestID)
+		return ctx.Value(contextKey{}).(string), nil
+	}, lambda.WithContext(ctx)))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `"base"`, string(body))
+}
+
+func TestBinaryResponseIsNotStreamed(t *testing.T) {
+	payload := append([]byte("\x89PNG"), make([]byte, 64)...)
+	server := httptest.NewServer(NewHandler(FunctionURL, func() (io.Reader, error) {
+		return bytes.NewReader(payload), nil
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, payload, body)
+}
+
+func TestInvokeContextHasDeadline(t *testing.T) {
+	server := httptest.NewServer(NewHandler(FunctionURL, func(ctx context.Context) (string, error) {
+		deadline, ok := ctx.Deadline()
+		require.True(t, ok)
+		assert.WithinDuration(t, time.Now().Add(invokeTimeout), deadline, time.Minute)
+		return "ok", nil
+	}))
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
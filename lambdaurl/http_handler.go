@@ -7,7 +7,9 @@
 package lambdaurl
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/base64"
 	"io"
 	"net/http"
@@ -16,6 +18,7 @@ import (
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
+	"github.com/aws/aws-lambda-go/lambdahttp"
 )
 
 type detectContentTypeContextKey struct{}
@@ -40,6 +43,19 @@ func WithDetectContentType(detectContentType bool) lambda.Option {
 	return lambda.WithContextValue(detectContentTypeContextKey{}, detectContentType)
 }
 
+type maxRequestBodyBytesContextKey struct{}
+
+// WithMaxRequestBodyBytes limits the size of the request body readable by the handler.
+// Reads past the limit return an error, matching the behavior of http.MaxBytesReader.
+// A limit less than or equal to zero disables the check.
+//
+// Usage:
+//
+//	lambdaurl.Start(handler, lambdaurl.WithMaxRequestBodyBytes(1<<20))
+func WithMaxRequestBodyBytes(n int64) lambda.Option {
+	return lambda.WithContextValue(maxRequestBodyBytesContextKey{}, n)
+}
+
 type httpResponseWriter struct {
 	detectContentType bool
 	header            http.Header
@@ -105,23 +121,11 @@ func RequestFromContext(ctx context.Context) (*events.LambdaFunctionURLRequest,
 func Wrap(handler http.Handler) func(context.Context, *events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
 	return func(ctx context.Context, request *events.LambdaFunctionURLRequest) (*events.LambdaFunctionURLStreamingResponse, error) {
 
-		var body io.Reader = strings.NewReader(request.Body)
-		if request.IsBase64Encoded {
-			body = base64.NewDecoder(base64.StdEncoding, body)
-		}
-		url := "https://" + request.RequestContext.DomainName + request.RawPath
-		if request.RawQueryString != "" {
-			url += "?" + request.RawQueryString
-		}
 		ctx = context.WithValue(ctx, requestContextKey{}, request)
-		httpRequest, err := http.NewRequestWithContext(ctx, request.RequestContext.HTTP.Method, url, body)
+		httpRequest, err := newHTTPRequest(ctx, request)
 		if err != nil {
 			return nil, err
 		}
-		httpRequest.RemoteAddr = request.RequestContext.HTTP.SourceIP
-		for k, v := range request.Headers {
-			httpRequest.Header.Add(k, v)
-		}
 
 		ready := make(chan header) // Signals when it's OK to start returning the response body to Lambda
 		r, w := io.Pipe()
@@ -129,6 +133,9 @@ func Wrap(handler http.Handler) func(context.Context, *events.LambdaFunctionURLR
 		if detectContentType, ok := ctx.Value(detectContentTypeContextKey{}).(bool); ok {
 			responseWriter.detectContentType = detectContentType
 		}
+		if maxBytes, ok := ctx.Value(maxRequestBodyBytesContextKey{}).(int64); ok && maxBytes > 0 {
+			httpRequest.Body = http.MaxBytesReader(responseWriter, httpRequest.Body, maxBytes)
+		}
 		go func() {
 			defer close(ready)
 			defer w.Close() // TODO: recover and CloseWithError the any panic value once the runtime API client supports plumbing fatal errors through the reader
@@ -155,6 +162,77 @@ func Wrap(handler http.Handler) func(context.Context, *events.LambdaFunctionURLR
 	}
 }
 
+// newHTTPRequest rebuilds the *http.Request that the Function URL received from the client.
+func newHTTPRequest(ctx context.Context, request *events.LambdaFunctionURLRequest) (*http.Request, error) {
+	body := []byte(request.Body)
+	if request.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = decoded
+	}
+	url := "https://" + request.RequestContext.DomainName + request.RawPath
+	if request.RawQueryString != "" {
+		url += "?" + request.RawQueryString
+	}
+	// http.NewRequest derives ContentLength from a *bytes.Reader, matching what http.ListenAndServe would provide
+	httpRequest, err := http.NewRequestWithContext(ctx, request.RequestContext.HTTP.Method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.RemoteAddr = request.RequestContext.HTTP.SourceIP
+	if proto := request.RequestContext.HTTP.Protocol; proto != "" {
+		if major, minor, ok := http.ParseHTTPVersion(proto); ok {
+			httpRequest.Proto, httpRequest.ProtoMajor, httpRequest.ProtoMinor = proto, major, minor
+		}
+	}
+	for k, v := range request.Headers {
+		httpRequest.Header.Add(k, v)
+	}
+
+	// The Function URL moves the Cookie header into the cookies array, which is the authoritative source.
+	if len(request.Cookies) > 0 {
+		httpRequest.Header.Set("Cookie", strings.Join(request.Cookies, "; "))
+	}
+	if host := httpRequest.Header.Get("Host"); host != "" {
+		httpRequest.Host = host
+	}
+	httpRequest.Header.Del("Host")
+	setDefaultHeader(httpRequest.Header, "X-Forwarded-For", request.RequestContext.HTTP.SourceIP)
+	setDefaultHeader(httpRequest.Header, "X-Forwarded-Host", httpRequest.Host)
+	setDefaultHeader(httpRequest.Header, "X-Forwarded-Port", "443")
+	setDefaultHeader(httpRequest.Header, "X-Forwarded-Proto", "https")
+
+	// Function URLs only accept HTTPS, so the request is always TLS from the perspective of the handler.
+	httpRequest.TLS = &tls.ConnectionState{
+		Version:           tlsVersion(httpRequest.Header.Get("X-Amzn-Tls-Version")),
+		HandshakeComplete: true,
+		ServerName:        request.RequestContext.DomainName,
+	}
+	return httpRequest, nil
+}
+
+func setDefaultHeader(h http.Header, key, value string) {
+	if value != "" && h.Get(key) == "" {
+		h.Set(key, value)
+	}
+}
+
+func tlsVersion(name string) uint16 {
+	switch name {
+	case "TLSv1":
+		return tls.VersionTLS10
+	case "TLSv1.1":
+		return tls.VersionTLS11
+	case "TLSv1.2":
+		return tls.VersionTLS12
+	case "TLSv1.3":
+		return tls.VersionTLS13
+	}
+	return 0
+}
+
 // Start wraps a http.Handler and calls lambda.StartHandlerFunc
 // Only supports:
 //   - Lambda Function URLs configured with `InvokeMode: RESPONSE_STREAM`
@@ -163,3 +241,17 @@ func Wrap(handler http.Handler) func(context.Context, *events.LambdaFunctionURLR
 func Start(handler http.Handler, options ...lambda.Option) {
 	lambda.StartHandlerFunc(Wrap(handler), options...)
 }
+
+// ListenAndServe serves a http.Handler on the TCP network address addr for local development.
+// Each request is converted into a Lambda Function URL event and passed through Wrap,
+// so the handler sees the same *http.Request as it would when deployed.
+//
+// Usage:
+//
+//	if os.Getenv("AWS_LAMBDA_RUNTIME_API") == "" {
+//	        log.Fatal(lambdaurl.ListenAndServe("localhost:8080", handler))
+//	}
+//	lambdaurl.Start(handler)
+func ListenAndServe(addr string, handler http.Handler, options ...lambda.Option) error {
+	return lambdahttp.ListenAndServe(addr, lambdahttp.FunctionURL, Wrap(handler), options...)
+}
@@ -7,6 +7,7 @@ package lambdaurl
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	_ "embed"
 	"encoding/json"
 	"io"
@@ -213,6 +214,70 @@ func TestRequestContext(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestWrapRequestFidelity(t *testing.T) {
+	var req events.LambdaFunctionURLRequest
+	require.NoError(t, json.Unmarshal(helloRequest, &req))
+	req.Headers["cookie"] = "stale=value"
+	handler := Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "lambda-url-id.lambda-url.us-west-2.on.aws", r.Host)
+		assert.Empty(t, r.Header.Get("Host"))
+		assert.Equal(t, "HTTP/1.1", r.Proto)
+		assert.Equal(t, int64(18), r.ContentLength)
+		require.NotNil(t, r.TLS)
+		assert.Equal(t, uint16(tls.VersionTLS12), r.TLS.Version)
+		assert.Equal(t, "lambda-url-id.lambda-url.us-west-2.on.aws", r.TLS.ServerName)
+		assert.Equal(t, "127.0.0.1", r.Header.Get("X-Forwarded-For"))
+		assert.Equal(t, "https", r.Header.Get("X-Forwarded-Proto"))
+		assert.Equal(t, "lambda-url-id.lambda-url.us-west-2.on.aws", r.Header.Get("X-Forwarded-Host"))
+		assert.Equal(t, "foo=bar; hello=hello", r.Header.Get("Cookie"))
+		cookies := r.Cookies()
+		require.Len(t, cookies, 2)
+		assert.Equal(t, "bar", cookies[0].Value)
+		assert.Equal(t, "hello", cookies[1].Value)
+		assert.Equal(t, []string{"world"}, r.URL.Query()["hello"])
+	}))
+	_, err := handler(context.Background(), &req)
+	require.NoError(t, err)
+}
+
+func TestWrapMaxRequestBodyBytes(t *testing.T) {
+	for name, params := range map[string]struct {
+		limit       int64
+		expectError bool
+	}{
+		"under limit": {limit: 18},
+		"over limit":  {limit: 17, expectError: true},
+		"disabled":    {limit: 0},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var req events.LambdaFunctionURLRequest
+			require.NoError(t, json.Unmarshal(helloRequest, &req))
+			var readErr error
+			handler := Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_, readErr = ioutil.ReadAll(r.Body)
+			}))
+			ctx := context.WithValue(context.Background(), maxRequestBodyBytesContextKey{}, params.limit)
+			res, err := handler(ctx, &req)
+			require.NoError(t, err)
+			_, err = ioutil.ReadAll(res)
+			require.NoError(t, err)
+			if params.expectError {
+				assert.Error(t, readErr)
+			} else {
+				assert.NoError(t, readErr)
+			}
+		})
+	}
+}
+
+func TestWrapInvalidBase64Body(t *testing.T) {
+	var req events.LambdaFunctionURLRequest
+	require.NoError(t, json.Unmarshal(base64EncodedBodyRequest, &req))
+	req.Body = "not base64!"
+	_, err := Wrap(http.NotFoundHandler())(context.Background(), &req)
+	assert.Error(t, err)
+}
+
 func TestStartViaEmulator(t *testing.T) {
 	addr1 := "localhost:" + strconv.Itoa(6001)
 	addr2 := "localhost:" + strconv.Itoa(7001)
@@ -0,0 +1,76 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package websocket
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ConnectionStore tracks the IDs of open WebSocket connections.
+// Implementations backed by a database allow connections to be shared across function instances.
+type ConnectionStore interface {
+	Add(ctx context.Context, connectionID string) error
+	Remove(ctx context.Context, connectionID string) error
+	List(ctx context.Context) ([]string, error)
+}
+
+// MemoryConnectionStore is a ConnectionStore local to the current function instance, which is mostly useful for tests.
+type MemoryConnectionStore struct {
+	mu          sync.Mutex
+	connections map[string]struct{}
+}
+
+// Add implements ConnectionStore.
+func (s *MemoryConnectionStore) Add(_ context.Context, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.connections == nil {
+		s.connections = map[string]struct{}{}
+	}
+	s.connections[connectionID] = struct{}{}
+	return nil
+}
+
+// Remove implements ConnectionStore.
+func (s *MemoryConnectionStore) Remove(_ context.Context, connectionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.connections, connectionID)
+	return nil
+}
+
+// List implements ConnectionStore. The connection IDs are sorted.
+func (s *MemoryConnectionStore) List(_ context.Context) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.connections))
+	for id := range s.connections {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Broadcast posts data to every connection in store.
+// Connections that no longer exist are removed from store.
+// The first error, other than ErrGone, is returned after attempting all of the connections.
+func Broadcast(ctx context.Context, sender Sender, store ConnectionStore, data []byte) error {
+	ids, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+	var firstErr error
+	for _, id := range ids {
+		err := sender.PostToConnection(ctx, id, data)
+		if errors.Is(err, ErrGone) {
+			err = store.Remove(ctx, id)
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,8 @@
+// Package websocket provides helpers for implementing Amazon API Gateway WebSocket APIs.
+//
+// A Router dispatches events.APIGatewayWebsocketProxyRequest events to handlers by route key,
+// including the $connect, $disconnect and $default routes. A Sender posts messages back to
+// connected clients through the API Gateway Management API.
+//
+// See https://docs.aws.amazon.com/apigateway/latest/developerguide/apigateway-websocket-api.html
+package websocket
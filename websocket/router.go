@@ -0,0 +1,126 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package websocket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Predefined route keys of API Gateway WebSocket APIs.
+const (
+	RouteConnect    = "$connect"
+	RouteDisconnect = "$disconnect"
+	RouteDefault    = "$default"
+)
+
+// HandlerFunc handles a single WebSocket route.
+// The returned response is only sent back to the client for $connect, or for routes with a route response configured.
+type HandlerFunc func(ctx context.Context, request *events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// Router dispatches WebSocket events to handlers by RequestContext.RouteKey.
+type Router struct {
+	routes      map[string]HandlerFunc
+	connections ConnectionStore
+}
+
+// RouterOption configures a Router.
+type RouterOption func(*Router)
+
+// WithConnectionStore tracks connections in store.
+// A connection is added after a successful $connect, and removed on $disconnect.
+func WithConnectionStore(store ConnectionStore) RouterOption {
+	return func(r *Router) {
+		r.connections = store
+	}
+}
+
+// NewRouter returns an empty Router.
+//
+// Usage:
+//
+//	router := websocket.NewRouter()
+//	router.Handle(websocket.RouteConnect, onConnect)
+//	router.Handle("sendMessage", onSendMessage)
+//	lambda.Start(router.Invoke)
+func NewRouter(options ...RouterOption) *Router {
+	r := &Router{routes: map[string]HandlerFunc{}}
+	for _, option := range options {
+		option(r)
+	}
+	return r
+}
+
+// Handle registers the handler for a route key. Registering a route key twice replaces the previous handler.
+func (r *Router) Handle(routeKey string, handler HandlerFunc) {
+	r.routes[routeKey] = handler
+}
+
+// Invoke dispatches the request to the handler registered for its route key.
+// Requests for route keys without a handler go to the $default handler, if one was registered.
+// Routes without a handler, such as an unhandled $connect or $disconnect, respond with 200 OK.
+func (r *Router) Invoke(ctx context.Context, request *events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	routeKey := request.RequestContext.RouteKey
+	ctx = context.WithValue(ctx, requestContextKey{}, request)
+	handler, ok := r.routes[routeKey]
+	if !ok {
+		switch routeKey {
+		case RouteConnect, RouteDisconnect:
+			handler = ok200
+		default:
+			if handler, ok = r.routes[RouteDefault]; !ok {
+				return events.APIGatewayProxyResponse{}, fmt.Errorf("websocket: no handler for route key %q", routeKey)
+			}
+		}
+	}
+	response, err := handler(ctx, request)
+	if err != nil || r.connections == nil {
+		return response, err
+	}
+
+	connectionID := request.RequestContext.ConnectionID
+	switch routeKey {
+	case RouteConnect:
+		if response.StatusCode == 0 || (response.StatusCode >= 200 && response.StatusCode < 300) {
+			err = r.connections.Add(ctx, connectionID)
+		}
+	case RouteDisconnect:
+		err = r.connections.Remove(ctx, connectionID)
+	}
+	return response, err
+}
+
+func ok200(_ context.Context, _ *events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+}
+
+type requestContextKey struct{}
+
+// RequestFromContext returns the *events.APIGatewayWebsocketProxyRequest being handled by a Router.
+func RequestFromContext(ctx context.Context) (*events.APIGatewayWebsocketProxyRequest, bool) {
+	request, ok := ctx.Value(requestContextKey{}).(*events.APIGatewayWebsocketProxyRequest)
+	return request, ok
+}
+
+// ConnectionID returns the ID of the connection that sent the request being handled by a Router.
+func ConnectionID(ctx context.Context) string {
+	if request, ok := RequestFromContext(ctx); ok {
+		return request.RequestContext.ConnectionID
+	}
+	return ""
+}
+
+// Authorizer returns the context set by the Lambda authorizer of the $connect route.
+// API Gateway includes it in the request of every route for the lifetime of the connection.
+func Authorizer(request *events.APIGatewayWebsocketProxyRequest) map[string]interface{} {
+	authorizer, _ := request.RequestContext.Authorizer.(map[string]interface{})
+	return authorizer
+}
+
+// PrincipalID returns the principal ID returned by the Lambda authorizer of the $connect route.
+func PrincipalID(request *events.APIGatewayWebsocketProxyRequest) string {
+	principalID, _ := Authorizer(request)["principalId"].(string)
+	return principalID
+}
@@ -0,0 +1,89 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package websocket
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequest(routeKey, connectionID string) *events.APIGatewayWebsocketProxyRequest {
+	return &events.APIGatewayWebsocketProxyRequest{
+		RequestContext: events.APIGatewayWebsocketProxyRequestContext{
+			RouteKey:     routeKey,
+			ConnectionID: connectionID,
+			Authorizer:   map[string]interface{}{"principalId": "user-1", "tenant": "acme"},
+		},
+	}
+}
+
+func TestRouterDispatch(t *testing.T) {
+	var calls []string
+	handler := func(name string) HandlerFunc {
+		return func(ctx context.Context, request *events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+			calls = append(calls, name+":"+ConnectionID(ctx))
+			return events.APIGatewayProxyResponse{StatusCode: 200, Body: name}, nil
+		}
+	}
+	router := NewRouter()
+	router.Handle(RouteConnect, handler("connect"))
+	router.Handle("sendMessage", handler("sendMessage"))
+	router.Handle(RouteDefault, handler("default"))
+
+	for routeKey, expectBody := range map[string]string{
+		RouteConnect:    "connect",
+		"sendMessage":   "sendMessage",
+		"unknown":       "default",
+		RouteDisconnect: "",
+	} {
+		response, err := router.Invoke(context.Background(), newRequest(routeKey, "abc="))
+		require.NoError(t, err)
+		assert.Equal(t, 200, response.StatusCode)
+		assert.Equal(t, expectBody, response.Body)
+	}
+	assert.ElementsMatch(t, []string{"connect:abc=", "sendMessage:abc=", "default:abc="}, calls)
+}
+
+func TestRouterNoDefault(t *testing.T) {
+	_, err := NewRouter().Invoke(context.Background(), newRequest("sendMessage", "abc="))
+	assert.EqualError(t, err, `websocket: no handler for route key "sendMessage"`)
+}
+
+func TestRouterConnectionStore(t *testing.T) {
+	store := &MemoryConnectionStore{}
+	router := NewRouter(WithConnectionStore(store))
+	router.Handle(RouteConnect, func(ctx context.Context, request *events.APIGatewayWebsocketProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if request.RequestContext.ConnectionID == "denied" {
+			return events.APIGatewayProxyResponse{StatusCode: 403}, nil
+		}
+		if request.RequestContext.ConnectionID == "failed" {
+			return events.APIGatewayProxyResponse{}, errors.New("barf")
+		}
+		return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+	})
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "denied", "failed"} {
+		_, _ = router.Invoke(ctx, newRequest(RouteConnect, id))
+	}
+	ids, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+
+	_, err = router.Invoke(ctx, newRequest(RouteDisconnect, "a"))
+	require.NoError(t, err)
+	ids, err = store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, ids)
+}
+
+func TestAuthorizer(t *testing.T) {
+	request := newRequest(RouteConnect, "abc=")
+	assert.Equal(t, "user-1", PrincipalID(request))
+	assert.Equal(t, "acme", Authorizer(request)["tenant"])
+	assert.Empty(t, PrincipalID(&events.APIGatewayWebsocketProxyRequest{}))
+}
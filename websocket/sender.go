@@ -0,0 +1,145 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package websocket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil" //nolint: staticcheck
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ErrGone is returned by a Sender when the connection no longer exists.
+var ErrGone = errors.New("websocket: connection is gone")
+
+// Sender posts messages back to WebSocket connections.
+type Sender interface {
+	// PostToConnection sends data to the client of a connection.
+	PostToConnection(ctx context.Context, connectionID string, data []byte) error
+	// DeleteConnection disconnects the client of a connection.
+	DeleteConnection(ctx context.Context, connectionID string) error
+}
+
+// Credentials are the AWS credentials used to sign requests to the API Gateway Management API.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+}
+
+// ManagementAPISender is a Sender that calls the API Gateway Management API.
+type ManagementAPISender struct {
+	endpoint    string
+	region      string
+	client      *http.Client
+	credentials func(ctx context.Context) (Credentials, error)
+	now         func() time.Time
+}
+
+// SenderOption configures a ManagementAPISender.
+type SenderOption func(*ManagementAPISender)
+
+// WithHTTPClient sets the HTTP client used to call the API Gateway Management API.
+// The default is http.DefaultClient.
+func WithHTTPClient(client *http.Client) SenderOption {
+	return func(s *ManagementAPISender) {
+		s.client = client
+	}
+}
+
+// WithRegion sets the region used to sign requests. The default is the AWS_REGION environment variable.
+func WithRegion(region string) SenderOption {
+	return func(s *ManagementAPISender) {
+		s.region = region
+	}
+}
+
+// WithCredentials sets a function called to retrieve the credentials for each request.
+// The default reads the credentials that Lambda sets in the environment of the function.
+// Requests are sent unsigned when the access key ID is empty, such as when sending to a local stand-in.
+func WithCredentials(credentials func(ctx context.Context) (Credentials, error)) SenderOption {
+	return func(s *ManagementAPISender) {
+		s.credentials = credentials
+	}
+}
+
+// NewSender returns a Sender for the API Gateway Management API at endpoint,
+// which is of the format "https://{api-id}.execute-api.{region}.amazonaws.com/{stage}".
+// See EndpointFromRequest.
+func NewSender(endpoint string, options ...SenderOption) *ManagementAPISender {
+	s := &ManagementAPISender{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		region:      os.Getenv("AWS_REGION"),
+		client:      http.DefaultClient,
+		credentials: environmentCredentials,
+		now:         time.Now,
+	}
+	for _, option := range options {
+		option(s)
+	}
+	return s
+}
+
+// EndpointFromRequest returns the API Gateway Management API endpoint for the API and stage that sent the request.
+// The region of the current function is assumed.
+func EndpointFromRequest(request *events.APIGatewayWebsocketProxyRequest) string {
+	return fmt.Sprintf("https://%s.execute-api.%s.amazonaws.com/%s", request.RequestContext.APIID, os.Getenv("AWS_REGION"), request.RequestContext.Stage)
+}
+
+func environmentCredentials(_ context.Context) (Credentials, error) {
+	return Credentials{
+		AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}, nil
+}
+
+// PostToConnection implements Sender.
+func (s *ManagementAPISender) PostToConnection(ctx context.Context, connectionID string, data []byte) error {
+	return s.do(ctx, http.MethodPost, connectionID, data)
+}
+
+// DeleteConnection implements Sender.
+func (s *ManagementAPISender) DeleteConnection(ctx context.Context, connectionID string) error {
+	return s.do(ctx, http.MethodDelete, connectionID, nil)
+}
+
+func (s *ManagementAPISender) do(ctx context.Context, method, connectionID string, body []byte) error {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint+"/@connections/"+uriEncode(connectionID, true), reader)
+	if err != nil {
+		return err
+	}
+	credentials, err := s.credentials(ctx)
+	if err != nil {
+		return fmt.Errorf("websocket: failed to retrieve credentials: %v", err)
+	}
+	if credentials.AccessKeyID != "" {
+		signRequest(req, body, credentials, s.region, "execute-api", s.now())
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	message, _ := ioutil.ReadAll(io.LimitReader(res.Body, 4096))
+	switch {
+	case res.StatusCode == http.StatusGone:
+		return ErrGone
+	case res.StatusCode < 200 || res.StatusCode > 299:
+		return fmt.Errorf("websocket: %s %s: status %d: %s", method, connectionID, res.StatusCode, message)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2026 Amazon.com, Inc. or its affiliates. All Rights Reserved.
+
+package websocket
+
+import (
+	"context"
+	"io/ioutil" //nolint: staticcheck
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// managementAPI is a local stand-in for the API Gateway Management API
+type managementAPI struct {
+	mu          sync.Mutex
+	connections map[string][]string
+	requests    []*http.Request
+}
+
+func (m *managementAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests = append(m.requests, r)
+	id := strings.TrimPrefix(r.URL.Path, "/stage/@connections/")
+	messages, ok := m.connections[id]
+	if !ok {
+		w.WriteHeader(http.StatusGone)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		body, _ := ioutil.ReadAll(r.Body)
+		m.connections[id] = append(messages, string(body))
+	case http.MethodDelete:
+		delete(m.connections, id)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+func TestManagementAPISender(t *testing.T) {
+	api := &managementAPI{connections: map[string][]string{"abc=": nil}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender := NewSender(server.URL+"/stage", WithHTTPClient(server.Client()), WithRegion("us-west-2"), WithCredentials(func(context.Context) (Credentials, error) {
+		return Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "token"}, nil
+	}))
+	ctx := context.Background()
+
+	require.NoError(t, sender.PostToConnection(ctx, "abc=", []byte("hello")))
+	assert.Equal(t, []string{"hello"}, api.connections["abc="])
+	require.Len(t, api.requests, 1)
+	assert.Equal(t, "/stage/@connections/abc%3D", api.requests[0].URL.EscapedPath())
+	assert.Contains(t, api.requests[0].Header.Get("Authorization"), "Credential=AKID/")
+	assert.Contains(t, api.requests[0].Header.Get("Authorization"), "/us-west-2/execute-api/aws4_request")
+	assert.Equal(t, "token", api.requests[0].Header.Get("X-Amz-Security-Token"))
+
+	require.NoError(t, sender.DeleteConnection(ctx, "abc="))
+	assert.ErrorIs(t, sender.PostToConnection(ctx, "abc=", []byte("hello")), ErrGone)
+}
+
+func TestManagementAPISenderUnsigned(t *testing.T) {
+	api := &managementAPI{connections: map[string][]string{"a": nil}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender := NewSender(server.URL+"/stage/", WithCredentials(func(context.Context) (Credentials, error) {
+		return Credentials{}, nil
+	}))
+
+	require.NoError(t, sender.PostToConnection(context.Background(), "a", []byte("hello")))
+	assert.Empty(t, api.requests[0].Header.Get("Authorization"))
+}
+
+func TestBroadcast(t *testing.T) {
+	api := &managementAPI{connections: map[string][]string{"a": nil, "b": nil}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+	sender := NewSender(server.URL+"/stage", WithCredentials(func(context.Context) (Credentials, error) {
+		return Credentials{}, nil
+	}))
+	store := &MemoryConnectionStore{}
+	ctx := context.Background()
+	for _, id := range []string{"a", "b", "gone"} {
+		require.NoError(t, store.Add(ctx, id))
+	}
+
+	require.NoError(t, Broadcast(ctx, sender, store, []byte("hi")))
+	assert.Equal(t, []string{"hi"}, api.connections["a"])
+	assert.Equal(t, []string{"hi"}, api.connections["b"])
+	ids, err := store.List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, ids)
+}